@@ -2,26 +2,74 @@ package admin
 
 import (
 	"errors"
-	"net/mail"
+	"regexp"
 	"strings"
 
 	"example.com/portfolio/db"
 	"example.com/portfolio/utils"
+	"example.com/portfolio/validation"
 )
 
 type Login struct {
-	Login    string `json:"login" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Login    string `json:"login"`
+	Password string `json:"password"`
 }
 
 type Address struct {
-	Username string `json:"username" binding:"required"`
-	Email    string `json:"email" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// ErrTaken is returned by SignUp when the username or email is already
+// registered.
+var ErrTaken = errors.New("the username or email is already registered")
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// Validate reports every field of the sign up that cannot be accepted.
+// Passwords are limited to 72 bytes, the most bcrypt uses.
+func (a *Address) Validate() error {
+	a.Username = strings.TrimSpace(a.Username)
+	a.Email = strings.TrimSpace(a.Email)
+
+	var errs validation.Errors
+	if errs.Required("username", a.Username) && errs.MinLength("username", a.Username, 3) &&
+		errs.MaxLength("username", a.Username, 32) {
+		errs.Match("username", a.Username, usernamePattern, "may only contain letters, digits, '.', '-' and '_'")
+	}
+	if errs.Required("email", a.Email) {
+		errs.Email("email", a.Email)
+	}
+	if errs.Required("password", a.Password) && errs.MinLength("password", a.Password, 8) && len(a.Password) > 72 {
+		errs.Add("password", "must be at most 72 bytes")
+	}
+	return errs.Err()
+}
+
+// Validate reports the missing fields of a login.
+func (l *Login) Validate() error {
+	var errs validation.Errors
+	errs.Required("login", l.Login)
+	errs.Required("password", l.Password)
+	return errs.Err()
 }
 
 func (a *Address) SignUp() error {
-	_, err := mail.ParseAddress(a.Email)
+	if err := a.Validate(); err != nil {
+		return err
+	}
+
+	var taken int
+	err := db.DB.QueryRow("SELECT COUNT(*) FROM signUp WHERE username = ? OR email = ?", a.Username, a.Email).
+		Scan(&taken)
+	if err != nil {
+		return err
+	}
+	if taken > 0 {
+		return ErrTaken
+	}
+
 	query := "INSERT INTO signUp (username, email, password) VALUES (?, ?, ?) "
 	stmt, err := db.DB.Prepare(query)
 
@@ -31,12 +79,12 @@ func (a *Address) SignUp() error {
 	defer stmt.Close()
 
 	hashedpass, err := utils.HashPassword(a.Password)
-
-	_, err = stmt.Exec(a.Username, a.Email, hashedpass)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	_, err = stmt.Exec(a.Username, a.Email, hashedpass)
+	return err
 
 }
 
@@ -0,0 +1,172 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"example.com/portfolio/db"
+)
+
+const maxQueryLength = 100
+
+var (
+	emailPattern  = regexp.MustCompile(`\S+@\S+`)
+	numberPattern = regexp.MustCompile(`\+?\d[\d\s\-()]{4,}\d`)
+)
+
+type QueryStat struct {
+	Query        string  `json:"query"`
+	Searches     int     `json:"searches"`
+	AvgResults   float64 `json:"avg_results"`
+	Clicks       int     `json:"clicks"`
+	ClickThrough float64 `json:"click_through"`
+	LastSearched string  `json:"last_searched"`
+}
+
+type ClickStat struct {
+	ContentID int64  `json:"content_id"`
+	Title     string `json:"title"`
+	Clicks    int    `json:"clicks"`
+}
+
+type Report struct {
+	Days        int         `json:"days"`
+	TopQueries  []QueryStat `json:"top_queries"`
+	ZeroResults []QueryStat `json:"zero_results"`
+	Clicks      []ClickStat `json:"clicks"`
+}
+
+// Normalize lowercases a search query, collapses whitespace and replaces
+// anything that looks like an email address or phone number, so the log
+// never holds personal data that visitors type into the search box.
+func Normalize(q string) string {
+	q = strings.ToLower(strings.Join(strings.Fields(q), " "))
+	q = emailPattern.ReplaceAllString(q, "<email>")
+	q = numberPattern.ReplaceAllString(q, "<number>")
+
+	if r := []rune(q); len(r) > maxQueryLength {
+		q = string(r[:maxQueryLength])
+	}
+	return q
+}
+
+// LogSearch records a search and its number of results in the background.
+// The client's IP and other request details are deliberately not stored.
+func LogSearch(query, language, category string, results int) {
+	q := Normalize(query)
+	if q == "" {
+		return
+	}
+	go func() {
+		_, err := db.DB.ExecContext(context.Background(),
+			"INSERT INTO search_log (query, language, category, results) VALUES (?, ?, ?, ?)",
+			q, language, category, results)
+		if err != nil {
+			log.Printf("⚠️ Could not log search: %v", err)
+		}
+	}()
+}
+
+// LogClick records that a search result was opened, in the background.
+func LogClick(query string, contentID int64) {
+	q := Normalize(query)
+	if q == "" {
+		return
+	}
+	go func() {
+		_, err := db.DB.ExecContext(context.Background(),
+			"INSERT INTO search_clicks (query, content_id) VALUES (?, ?)",
+			q, contentID)
+		if err != nil {
+			log.Printf("⚠️ Could not log search click: %v", err)
+		}
+	}()
+}
+
+// GetReport summarizes the searches of the last days: the most frequent
+// queries with their click-through rate, the queries that found nothing
+// and the content most often opened from search results.
+func GetReport(days, limit int) (Report, error) {
+	since := fmt.Sprintf("-%d days", days)
+	r := Report{Days: days, TopQueries: []QueryStat{}, ZeroResults: []QueryStat{}, Clicks: []ClickStat{}}
+
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT l.query, COUNT(*), AVG(l.results), MAX(l.created_at),
+			   (SELECT COUNT(*) FROM search_clicks c
+				WHERE c.query = l.query AND c.created_at >= datetime('now', ?))
+		FROM search_log l
+		WHERE l.created_at >= datetime('now', ?)
+		GROUP BY l.query
+		ORDER BY COUNT(*) DESC, l.query ASC
+		LIMIT ?;
+	`, since, since, limit)
+	if err != nil {
+		return r, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	for rows.Next() {
+		var s QueryStat
+		if err := rows.Scan(&s.Query, &s.Searches, &s.AvgResults, &s.LastSearched, &s.Clicks); err != nil {
+			rows.Close()
+			return r, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if s.Searches > 0 {
+			s.ClickThrough = float64(s.Clicks) / float64(s.Searches)
+		}
+		r.TopQueries = append(r.TopQueries, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return r, err
+	}
+
+	rows, err = db.DB.QueryContext(context.Background(), `
+		SELECT query, COUNT(*), MAX(created_at)
+		FROM search_log
+		WHERE created_at >= datetime('now', ?)
+		GROUP BY query
+		HAVING MAX(results) = 0
+		ORDER BY COUNT(*) DESC, query ASC
+		LIMIT ?;
+	`, since, limit)
+	if err != nil {
+		return r, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	for rows.Next() {
+		var s QueryStat
+		if err := rows.Scan(&s.Query, &s.Searches, &s.LastSearched); err != nil {
+			rows.Close()
+			return r, fmt.Errorf("failed to scan row: %w", err)
+		}
+		r.ZeroResults = append(r.ZeroResults, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return r, err
+	}
+
+	rows, err = db.DB.QueryContext(context.Background(), `
+		SELECT c.content_id, COALESCE(d.title, ''), COUNT(*)
+		FROM search_clicks c
+		LEFT JOIN blog_data d ON d.id = c.content_id
+		WHERE c.created_at >= datetime('now', ?)
+		GROUP BY c.content_id
+		ORDER BY COUNT(*) DESC, c.content_id ASC
+		LIMIT ?;
+	`, since, limit)
+	if err != nil {
+		return r, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var s ClickStat
+		if err := rows.Scan(&s.ContentID, &s.Title, &s.Clicks); err != nil {
+			return r, fmt.Errorf("failed to scan row: %w", err)
+		}
+		r.Clicks = append(r.Clicks, s)
+	}
+
+	return r, rows.Err()
+}
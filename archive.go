@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+
+	"example.com/portfolio/content"
+	"example.com/portfolio/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+// archive godoc
+// @Summary      Archive counts
+// @Description  Returns the number of published items of a language and category per year and per month, newest first, for archive navigation. Items not translated into the language count in the language they fall back to, as in GET /blogs/{page}. A year or month is listed with GET /blogs/{page}?from=...&to=....
+// @Tags         Content
+// @Produce      json
+// @Param        language           query   string  false  "Language (default en)"  Enums(en, ru, uz)
+// @Param        category           query   string  false  "Category (default blog)"  Enums(blog, project)
+// @Param        If-None-Match      header  string  false  "ETag of a cached copy"
+// @Param        If-Modified-Since  header  string  false  "Last-Modified time of a cached copy"
+// @Success      200  {object}  map[string]interface{}  "Counts per year, each with its months"
+// @Header       200  {string}  ETag           "Weak validator of the archive"
+// @Header       200  {string}  Cache-Control  "Caching policy, configurable with CACHE_CONTROL_ARCHIVE"
+// @Success      304  "Not modified"
+// @Failure      400  {object}  map[string]string  "Invalid parameters"
+// @Failure      500  {object}  map[string]string  "Failed to fetch archive"
+// @Router       /archive [get]
+func archive(c *gin.Context) {
+	language := c.DefaultQuery("language", "en")
+	if !content.ValidLanguage(language) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid language"})
+		return
+	}
+
+	category := c.DefaultQuery("category", "blog")
+	if !content.ValidType(category) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category"})
+		return
+	}
+
+	etag, modified, err := content.ListState("archive?" + language + "&" + category)
+	if err == nil && middlewares.NotModified(c, etag, modified) {
+		return
+	}
+
+	years, err := content.Archive(language, category)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch archive"})
+		return
+	}
+
+	total := 0
+	for _, y := range years {
+		total += y.Count
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"language": language,
+		"category": category,
+		"total":    total,
+		"years":    years,
+	})
+}
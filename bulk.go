@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"example.com/portfolio/content"
+	"example.com/portfolio/validation"
+	"example.com/portfolio/webhooks"
+	"github.com/gin-gonic/gin"
+)
+
+type bulkRequest struct {
+	IDs        []int64          `json:"ids"`
+	Operations []content.BulkOp `json:"operations"`
+}
+
+// bulk godoc
+// @Summary      Bulk content operations
+// @Description  Applies operations (delete, set_featured, set_status, add_tag, remove_tag) in order to every listed content item in one transaction. If any item fails, nothing is changed and the per-item results explain why. delete cannot be combined with other operations.
+// @Security     TokenAuth
+// @Tags         content
+// @Accept       json
+// @Produce      json
+// @Param        request  body      bulkRequest  true  "Content IDs and operations"
+// @Success      200      {object}  map[string]interface{}  "Per-item results"
+// @Failure      400      {object}  map[string]string       "Invalid operations"
+// @Failure      422      {object}  map[string]interface{}  "Missing ids or operations, or batch rolled back; per-item results"
+// @Failure      500      {object}  map[string]string       "Failed to apply operations"
+// @Router       /bulk [post]
+func bulk(c *gin.Context) {
+	var in bulkRequest
+	if !bindJSON(c, &in) {
+		return
+	}
+	var errs validation.Errors
+	if len(in.IDs) == 0 {
+		errs.Add("ids", "is required")
+	}
+	if len(in.Operations) == 0 {
+		errs.Add("operations", "is required")
+	}
+	if len(errs) > 0 {
+		invalidInput(c, errs)
+		return
+	}
+
+	// The statuses before the change tell which items it publishes, to
+	// announce them to newsletter subscribers.
+	before := map[int64]string{}
+	for _, op := range in.Operations {
+		if op.Op == content.BulkSetStatus && op.Value == content.StatusPublished {
+			for _, id := range in.IDs {
+				if r, err := content.Revision(id); err == nil {
+					before[id] = r.Status
+				}
+			}
+			break
+		}
+	}
+
+	results, err := content.Bulk(in.IDs, in.Operations)
+	switch {
+	case errors.Is(err, content.ErrInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, content.ErrBulkRejected):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "No changes were made because some items failed",
+			"results": results,
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply operations"})
+		return
+	}
+
+	if in.Operations[0].Op == content.BulkDelete {
+		for _, r := range results {
+			webhooks.Emit(webhooks.EventContentDeleted, deletedContent{ID: r.ID})
+		}
+	} else {
+		var changed []int64
+		for _, r := range results {
+			if r.Changed {
+				changed = append(changed, r.ID)
+			}
+		}
+		emitChanged(webhooks.EventContentUpdated, changed)
+
+		for _, id := range changed {
+			status, ok := before[id]
+			if !ok || status == content.StatusPublished {
+				continue
+			}
+			if cnt, err := content.GetById(id); err == nil {
+				notifyPublished(status, cnt)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
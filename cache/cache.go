@@ -0,0 +1,178 @@
+// Package cache provides a bounded in-memory LRU cache with a per-entry
+// time to live.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type Stats struct {
+	Enabled   bool   `json:"enabled"`
+	Size      int    `json:"size"`
+	Capacity  int    `json:"capacity"`
+	TTL       string `json:"ttl"`
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Expired   uint64 `json:"expired"`
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// LRU is safe for concurrent use. When full, adding an entry evicts the
+// least recently used one.
+type LRU[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	enabled  bool
+	order    *list.List
+	items    map[K]*list.Element
+	// generation is incremented by Purge so that values loaded before an
+	// invalidation are not stored after it.
+	generation uint64
+
+	hits, misses, evictions, expired uint64
+}
+
+func New[K comparable, V any](capacity int, ttl time.Duration) *LRU[K, V] {
+	return &LRU[K, V]{
+		capacity: capacity,
+		ttl:      ttl,
+		enabled:  true,
+		order:    list.New(),
+		items:    map[K]*list.Element{},
+	}
+}
+
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	if !c.enabled {
+		return zero, false
+	}
+
+	el, ok := c.items[key]
+	if !ok {
+		c.misses++
+		return zero, false
+	}
+	e := el.Value.(*entry[K, V])
+	if time.Now().After(e.expires) {
+		c.remove(el)
+		c.expired++
+		c.misses++
+		return zero, false
+	}
+
+	c.order.MoveToFront(el)
+	c.hits++
+	return e.value, true
+}
+
+func (c *LRU[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(key, value)
+}
+
+func (c *LRU[K, V]) set(key K, value V) {
+	if !c.enabled || c.capacity <= 0 {
+		return
+	}
+
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[K, V])
+		e.value = value
+		e.expires = time.Now().Add(c.ttl)
+		c.order.MoveToFront(el)
+		return
+	}
+
+	for c.order.Len() >= c.capacity {
+		c.remove(c.order.Back())
+		c.evictions++
+	}
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expires: time.Now().Add(c.ttl)})
+}
+
+// GetOrLoad returns the cached value for key, or calls load and caches its
+// result. Errors are not cached. If the cache is purged while load runs,
+// the loaded value is returned but not stored, since it may be stale.
+func (c *LRU[K, V]) GetOrLoad(key K, load func() (V, error)) (V, error) {
+	if v, ok := c.Get(key); ok {
+		return v, nil
+	}
+
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	v, err := load()
+	if err != nil {
+		return v, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.set(key, v)
+	}
+	c.mu.Unlock()
+	return v, nil
+}
+
+func (c *LRU[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+}
+
+// Purge removes every entry.
+func (c *LRU[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.items = map[K]*list.Element{}
+	c.generation++
+}
+
+// SetEnabled turns the cache on or off. A disabled cache is empty, misses
+// every lookup and stores nothing.
+func (c *LRU[K, V]) SetEnabled(enabled bool) {
+	c.mu.Lock()
+	c.enabled = enabled
+	c.mu.Unlock()
+	if !enabled {
+		c.Purge()
+	}
+}
+
+func (c *LRU[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Stats{
+		Enabled:   c.enabled,
+		Size:      c.order.Len(),
+		Capacity:  c.capacity,
+		TTL:       c.ttl.String(),
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Expired:   c.expired,
+	}
+}
+
+func (c *LRU[K, V]) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*entry[K, V]).key)
+}
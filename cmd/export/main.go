@@ -0,0 +1,79 @@
+// Command export writes all published content to static files for
+// prerendering the frontend.
+//
+//	go run ./cmd/export -out content.json
+//	go run ./cmd/export -format tree -out public/data
+//	go run ./cmd/export -format hugo -out site
+//
+// json writes a single versioned bundle, to stdout with -out -. tree and
+// hugo write a directory, replacing files of earlier exports but not
+// removing files of content that no longer exists.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"example.com/portfolio/content"
+	"example.com/portfolio/db"
+	"example.com/portfolio/export"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	format := flag.String("format", export.FormatJSON, "export format: "+strings.Join(export.Formats, ", "))
+	out := flag.String("out", "", "output file for json (default content.json), directory otherwise (default export)")
+	flag.Parse()
+
+	_ = godotenv.Load()
+	db.Initdb()
+
+	etag, _, err := content.ListState(export.StateKey)
+	if err != nil {
+		log.Fatalf("❌ Could not load content: %v", err)
+	}
+	b, err := export.Build(etag)
+	if err != nil {
+		log.Fatalf("❌ Could not load content: %v", err)
+	}
+
+	switch *format {
+	case export.FormatJSON:
+		if *out == "" {
+			*out = "content.json"
+		}
+		err = writeBundle(b, *out)
+	case export.FormatTree, export.FormatHugo:
+		if *out == "" {
+			*out = "export"
+		}
+		err = export.Write(*format, b, export.Dir(*out))
+	default:
+		log.Fatalf("❌ Unknown format %q: must be one of %s", *format, strings.Join(export.Formats, ", "))
+	}
+	if err != nil {
+		log.Fatalf("❌ Could not write export: %v", err)
+	}
+
+	if *out != "-" {
+		fmt.Fprintf(os.Stderr, "✅ Exported %d items (revision %s) to %s\n", b.Count, b.Revision, *out)
+	}
+}
+
+func writeBundle(b export.Bundle, out string) error {
+	if out == "-" {
+		return export.WriteJSON(os.Stdout, b)
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := export.WriteJSON(f, b); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
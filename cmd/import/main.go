@@ -0,0 +1,146 @@
+// Command import creates or updates content from Markdown files with YAML
+// front matter, or from a WordPress or Ghost export.
+//
+//	go run ./cmd/import posts/ notes/hello.md archive.zip
+//	go run ./cmd/import -from wordpress export.xml
+//	go run ./cmd/import -from ghost -site https://blog.example.com -dry-run=false export.json
+//
+// Markdown arguments may be files, directories of Markdown files or zip
+// archives. Images referenced from a document are looked up relative to it
+// and uploaded to Cloudinary.
+//
+// Exports from other platforms are only reported on unless -dry-run=false
+// is given, so the mapping can be checked before anything is written.
+//
+// A running server does not notice what the import writes until its read
+// cache expires, after at most ten minutes. To serve the imported content
+// at once, purge the cache with POST /cache/purge or restart the server.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"example.com/portfolio/db"
+	"example.com/portfolio/importer"
+	"github.com/joho/godotenv"
+)
+
+const purgeNote = "A running server shows the changes once its cache expires; POST /cache/purge to show them now."
+
+func main() {
+	from := flag.String("from", "markdown", "format of the input: markdown, wordpress or ghost")
+	opts := importer.Options{}
+	flag.StringVar(&opts.Language, "language", "en", "language of posts when the export does not name one")
+	flag.StringVar(&opts.Type, "type", "blog", "content type of imported posts")
+	flag.StringVar(&opts.SiteURL, "site", "", "URL of the old site, used to resolve relative links")
+	flag.BoolVar(&opts.DryRun, "dry-run", true, "only report what a WordPress or Ghost import would do")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: import [flags] <file.md | dir | archive.zip>...")
+		fmt.Fprintln(os.Stderr, "       import -from wordpress|ghost [flags] <export>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	_ = godotenv.Load()
+	db.Initdb()
+
+	switch *from {
+	case "markdown":
+		importMarkdown(flag.Args())
+	case importer.SourceWordPress, importer.SourceGhost:
+		if flag.NArg() != 1 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		importExport(*from, flag.Arg(0), opts)
+	default:
+		log.Fatalf("❌ Unknown format %q", *from)
+	}
+}
+
+func importMarkdown(paths []string) {
+	files, err := importer.Load(paths)
+	if err != nil {
+		log.Fatalf("❌ Could not read import: %v", err)
+	}
+
+	var im importer.Importer
+	results := im.Import(files)
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+			fmt.Printf("✗ %s: %s\n", r.File, r.Error)
+			continue
+		}
+		fmt.Printf("✓ %s: %s #%d %q\n", r.File, r.Action, r.ID, r.Title)
+	}
+	if failed < len(results) {
+		fmt.Println(purgeNote)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func importExport(source, path string, opts importer.Options) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("❌ Could not read export: %v", err)
+	}
+	defer f.Close()
+
+	posts, err := importer.ParseExport(source, f, opts)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	var im importer.Importer
+	report := im.ImportPosts(source, posts, opts)
+	for _, item := range report.Items {
+		mark := "✓"
+		switch item.Action {
+		case importer.ActionSkipped:
+			mark = "-"
+		case importer.ActionFailed:
+			mark = "✗"
+		}
+		fmt.Printf("%s %s %q: %s", mark, item.SourceID, item.Title, item.Action)
+		if item.ID != 0 {
+			fmt.Printf(" #%d", item.ID)
+		}
+		if item.Error != "" {
+			fmt.Printf(": %s", item.Error)
+		}
+		fmt.Println()
+		if item.Action != importer.ActionSkipped && item.Action != importer.ActionFailed {
+			fmt.Printf("    %s/%s, %s, %s, tags: %s, featured: %t\n",
+				item.Language, item.Type, item.Status, item.Date, item.Tags, item.Featured)
+		}
+		for _, w := range item.Warnings {
+			fmt.Printf("    ! %s\n", w)
+		}
+	}
+
+	verb := ""
+	if report.DryRun {
+		verb = "would be "
+	}
+	fmt.Printf("\n%d %screated, %d %supdated, %d skipped, %d failed\n",
+		report.Created, verb, report.Updated, verb, report.Skipped, report.Failed)
+	if report.DryRun {
+		fmt.Println("Nothing was written; run again with -dry-run=false to import.")
+	} else {
+		fmt.Println(purgeNote)
+	}
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,136 @@
+// Command smtp-sink is a local SMTP server for trying out the newsletter.
+// It accepts every mail and prints it instead of delivering it.
+//
+//	go run ./cmd/smtp-sink
+//	SMTP_HOST=localhost SMTP_PORT=2525 go run .
+//
+// Confirmation and unsubscribe links can be copied from its output.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:2525", "address to listen on")
+	flag.Parse()
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Listening on %s", *addr)
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			log.Printf("⚠️ %v", err)
+			continue
+		}
+		go serve(conn)
+	}
+}
+
+// serve speaks just enough SMTP for net/smtp.SendMail, including AUTH,
+// which accepts any credentials.
+func serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(s string) { fmt.Fprint(conn, s+"\r\n") }
+
+	reply("220 smtp-sink ready")
+	var from string
+	var to []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb, arg, _ := strings.Cut(line, " ")
+		verb = strings.ToUpper(verb)
+
+		switch verb {
+		case "EHLO":
+			reply("250-smtp-sink")
+			reply("250-8BITMIME")
+			reply("250 AUTH PLAIN LOGIN")
+		case "HELO", "NOOP":
+			reply("250 OK")
+		case "AUTH":
+			reply("235 Authentication succeeded")
+		case "MAIL":
+			from, _, _ = strings.Cut(strings.TrimPrefix(arg, "FROM:"), " ")
+			to = nil
+			reply("250 OK")
+		case "RCPT":
+			to = append(to, strings.TrimPrefix(arg, "TO:"))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			data, err := readData(r)
+			if err != nil {
+				return
+			}
+			show(from, to, data)
+			reply("250 OK")
+		case "RSET":
+			from, to = "", nil
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func readData(r *bufio.Reader) (string, error) {
+	var b strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		if line == ".\r\n" || line == ".\n" {
+			return b.String(), nil
+		}
+		b.WriteString(strings.TrimPrefix(line, "."))
+	}
+}
+
+// show shows the headers that matter and the decoded body.
+func show(from string, to []string, data string) {
+	fmt.Printf("From %s to %s\n", from, strings.Join(to, ", "))
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		fmt.Printf("%s\n\n", data)
+		return
+	}
+
+	var dec mime.WordDecoder
+	for _, h := range []string{"Subject", "List-Unsubscribe"} {
+		if v := msg.Header.Get(h); v != "" {
+			if decoded, err := dec.DecodeHeader(v); err == nil {
+				v = decoded
+			}
+			fmt.Printf("%s: %s\n", h, v)
+		}
+	}
+
+	body := msg.Body
+	if strings.EqualFold(msg.Header.Get("Content-Transfer-Encoding"), "quoted-printable") {
+		body = quotedprintable.NewReader(body)
+	}
+	text, _ := io.ReadAll(body)
+	fmt.Printf("\n%s\n\n", strings.ReplaceAll(string(text), "\r\n", "\n"))
+}
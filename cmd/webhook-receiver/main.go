@@ -0,0 +1,79 @@
+// Command webhook-receiver is a local endpoint for trying out webhooks. It
+// prints every delivery it receives and checks its signature.
+//
+//	go run ./cmd/webhook-receiver -secret whsec_...
+//	go run ./cmd/webhook-receiver -secret whsec_... -fail 2
+//
+// Register http://localhost:9000/ as a webhook and send it a ping with
+// POST /webhooks/{id}/ping. -fail answers the first deliveries with 500,
+// to watch them being retried.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"example.com/portfolio/webhooks"
+)
+
+func main() {
+	addr := flag.String("addr", ":9000", "address to listen on")
+	secret := flag.String("secret", os.Getenv("WEBHOOK_SECRET"), "webhook secret (default $WEBHOOK_SECRET)")
+	fail := flag.Int("fail", 0, "answer this many deliveries with 500 first")
+	tolerance := flag.Duration("tolerance", 5*time.Minute, "maximum age of a signature")
+	flag.Parse()
+
+	if *secret == "" {
+		log.Println("⚠️ No secret given, signatures are not checked")
+	}
+
+	var (
+		mu       sync.Mutex
+		received int
+	)
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "could not read body", http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		received++
+		n := received
+		mu.Unlock()
+
+		fmt.Printf("#%d %s %s delivery %s\n", n, r.Method, r.Header.Get("X-Webhook-Event"), r.Header.Get("X-Webhook-Delivery"))
+		if *secret != "" {
+			if err := webhooks.Verify(*secret, r.Header.Get(webhooks.SignatureHeader), body, *tolerance); err != nil {
+				fmt.Printf("❌ %v\n\n", err)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			fmt.Println("✅ signature verified")
+		}
+
+		var pretty bytes.Buffer
+		if json.Indent(&pretty, body, "", "  ") == nil {
+			body = pretty.Bytes()
+		}
+		fmt.Printf("%s\n\n", body)
+
+		if n <= *fail {
+			http.Error(w, "failing on purpose", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	log.Printf("Listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"example.com/portfolio/comments"
+	"example.com/portfolio/validation"
+	"example.com/portfolio/webhooks"
+	"github.com/gin-gonic/gin"
+)
+
+// listComments godoc
+// @Summary      List comments
+// @Description  Returns the approved comments of a content item as a thread
+// @Tags         comments
+// @Produce      json
+// @Param        id   path      int  true  "Blog ID"
+// @Success      200  {object}  map[string]interface{}  "Comments"
+// @Failure      400  {object}  map[string]string       "Invalid blog ID"
+// @Failure      500  {object}  map[string]string       "Failed to fetch comments"
+// @Router       /blog/{id}/comments [get]
+func listComments(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blog ID"})
+		return
+	}
+
+	list, err := comments.GetApproved(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comments": list})
+}
+
+// postComment godoc
+// @Summary      Submit a comment
+// @Description  Adds a comment or a reply to an approved comment. New comments wait for moderation.
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int               true  "Blog ID"
+// @Param        comment  body      comments.Comment  true  "Name, body and optional parent_id"
+// @Success      201      {object}  map[string]interface{}  "Comment submitted for moderation"
+// @Failure      400      {object}  map[string]string       "Invalid input"
+// @Failure      404      {object}  map[string]string       "Blog not found"
+// @Failure      422      {object}  map[string]interface{}  "Validation failed; lists each failing field"
+// @Failure      429      {object}  map[string]string       "Daily comment limit reached"
+// @Failure      500      {object}  map[string]string       "Server or database error"
+// @Router       /blog/{id}/comments [post]
+func postComment(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blog ID"})
+		return
+	}
+
+	var cm comments.Comment
+	if !bindJSON(c, &cm) {
+		return
+	}
+	cm.ContentID = id
+
+	ip := c.ClientIP()
+
+	ok, err := comments.CanComment(ip)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Daily comment limit reached (5 per day)"})
+		return
+	}
+
+	if err := cm.Save(ip); err != nil {
+		switch {
+		case errors.Is(err, comments.ErrContentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		case errors.Is(err, comments.ErrParentNotFound), errors.Is(err, comments.ErrInvalid):
+			invalidInput(c, err)
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save the comment"})
+		}
+		return
+	}
+
+	msg := fmt.Sprintf("💬 New comment awaiting moderation\n\n📝 Post #%d\n👤 %s\n%s", cm.ContentID, cm.Name, cm.Body)
+	_ = sendTelegramMessage(botToken, adminID, msg)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Your comment was submitted and is awaiting moderation",
+		"comment": cm,
+	})
+}
+
+// moderationQueue godoc
+// @Summary      List comments for moderation
+// @Description  Returns comments with the given moderation status, newest first
+// @Security     TokenAuth
+// @Tags         comments
+// @Produce      json
+// @Param        status  query     string  false  "Moderation status (default pending)"  Enums(pending, approved, spam)
+// @Param        page    query     int     false  "Page number (default 1)"
+// @Success      200     {object}  map[string]interface{}  "Comments"
+// @Failure      400     {object}  map[string]string       "Invalid parameters"
+// @Failure      500     {object}  map[string]string       "Failed to fetch comments"
+// @Router       /comments [get]
+func moderationQueue(c *gin.Context) {
+	status := c.DefaultQuery("status", comments.StatusPending)
+	if !comments.ValidStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page number"})
+		return
+	}
+
+	list, err := comments.GetByStatus(status, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comments": list})
+}
+
+type commentStatus struct {
+	Status string `json:"status"`
+}
+
+// moderateComment godoc
+// @Summary      Moderate a comment
+// @Description  Sets the moderation status of a comment. Only approved comments are shown publicly.
+// @Security     TokenAuth
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int            true  "Comment ID"
+// @Param        status  body      commentStatus  true  "New status (pending, approved or spam)"
+// @Success      200     {object}  map[string]string  "Comment updated"
+// @Failure      400     {object}  map[string]string  "Invalid input"
+// @Failure      404     {object}  map[string]string  "Comment not found"
+// @Failure      422     {object}  map[string]interface{}  "Validation failed; lists each failing field"
+// @Failure      500     {object}  map[string]string  "Failed to update comment"
+// @Router       /comments/{id}/status [put]
+func moderateComment(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	var s commentStatus
+	if !bindJSON(c, &s) {
+		return
+	}
+	var errs validation.Errors
+	if errs.Required("status", s.Status) && !comments.ValidStatus(s.Status) {
+		errs.Add("status", "must be pending, approved or spam")
+	}
+	if len(errs) > 0 {
+		invalidInput(c, errs)
+		return
+	}
+
+	contentID, err := comments.ContentID(id)
+	if err == nil {
+		err = comments.SetStatus(id, s.Status)
+	}
+	if err != nil {
+		if errors.Is(err, comments.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment"})
+		return
+	}
+	emitChanged(webhooks.EventContentUpdated, []int64{contentID})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment updated successfully"})
+}
+
+// deleteComment godoc
+// @Summary      Delete a comment
+// @Description  Deletes a comment and all replies below it
+// @Security     TokenAuth
+// @Tags         comments
+// @Produce      json
+// @Param        id   path      int  true  "Comment ID"
+// @Success      200  {object}  map[string]string  "Comment deleted"
+// @Failure      400  {object}  map[string]string  "Invalid comment ID"
+// @Failure      404  {object}  map[string]string  "Comment not found"
+// @Failure      500  {object}  map[string]string  "Failed to delete comment"
+// @Router       /comments/{id} [delete]
+func deleteComment(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	contentID, err := comments.ContentID(id)
+	if err == nil {
+		err = comments.Delete(id)
+	}
+	if err != nil {
+		if errors.Is(err, comments.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		return
+	}
+	emitChanged(webhooks.EventContentUpdated, []int64{contentID})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
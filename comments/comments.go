@@ -0,0 +1,258 @@
+package comments
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"example.com/portfolio/db"
+	"example.com/portfolio/validation"
+)
+
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusSpam     = "spam"
+
+	dailyLimit    = 5
+	maxNameLength = 80
+	maxBodyLength = 2000
+	pageSize      = 20
+)
+
+var (
+	ErrContentNotFound = errors.New("content not found")
+	ErrParentNotFound  = errors.New("the comment you are replying to does not exist")
+	ErrNotFound        = errors.New("comment not found")
+	ErrInvalid         = errors.New("invalid comment")
+)
+
+type Comment struct {
+	ID        int64     `json:"id"`
+	ContentID int64     `json:"content_id"`
+	ParentID  *int64    `json:"parent_id,omitempty"`
+	Name      string    `json:"name"`
+	Body      string    `json:"body"`
+	Status    string    `json:"status,omitempty"`
+	IP        string    `json:"-"`
+	CreatedAt string    `json:"created_at"`
+	Replies   []Comment `json:"replies,omitempty"`
+}
+
+func ValidStatus(status string) bool {
+	return status == StatusPending || status == StatusApproved || status == StatusSpam
+}
+
+// Save stores a new comment on content c.ContentID in the moderation queue.
+// Replies must point to an approved comment on the same content.
+func (c *Comment) Save(ip string) error {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Body = strings.TrimSpace(c.Body)
+	var errs validation.Errors
+	if errs.Required("name", c.Name) {
+		errs.MaxLength("name", c.Name, maxNameLength)
+	}
+	if errs.Required("body", c.Body) {
+		errs.MaxLength("body", c.Body, maxBodyLength)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalid, errs)
+	}
+
+	var exists int
+	err := db.DB.QueryRowContext(context.Background(),
+		"SELECT COUNT(*) FROM blog_data WHERE id = ? AND status = 'published'", c.ContentID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists == 0 {
+		return ErrContentNotFound
+	}
+
+	if c.ParentID != nil {
+		err := db.DB.QueryRowContext(context.Background(), `
+			SELECT COUNT(*) FROM comments
+			WHERE id = ? AND content_id = ? AND status = ?
+		`, *c.ParentID, c.ContentID, StatusApproved).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists == 0 {
+			return ErrParentNotFound
+		}
+	}
+
+	res, err := db.DB.ExecContext(context.Background(), `
+		INSERT INTO comments (content_id, parent_id, name, body, status, ip)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, c.ContentID, c.ParentID, c.Name, c.Body, StatusPending, ip)
+	if err != nil {
+		return fmt.Errorf("failed to insert comment: %w", err)
+	}
+
+	c.ID, err = res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	c.Status = StatusPending
+	c.IP = ip
+
+	return db.DB.QueryRowContext(context.Background(),
+		"SELECT created_at FROM comments WHERE id = ?", c.ID).Scan(&c.CreatedAt)
+}
+
+func CanComment(ip string) (bool, error) {
+	var count int
+	query := `
+		SELECT COUNT(*)
+		FROM comments
+		WHERE ip = ? AND DATE(created_at) = DATE('now')
+	`
+	err := db.DB.QueryRow(query, ip).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count < dailyLimit, nil
+}
+
+// GetApproved returns the approved comments of a content item as a tree,
+// oldest first. Replies to comments that are not approved are left out.
+func GetApproved(contentID int64) ([]Comment, error) {
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT id, content_id, parent_id, name, body, created_at
+		FROM comments
+		WHERE content_id = ? AND status = ?
+		ORDER BY created_at ASC, id ASC
+	`, contentID, StatusApproved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	defer rows.Close()
+
+	var flat []Comment
+	for rows.Next() {
+		var (
+			c      Comment
+			parent sql.NullInt64
+		)
+		if err := rows.Scan(&c.ID, &c.ContentID, &parent, &c.Name, &c.Body, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if parent.Valid {
+			c.ParentID = &parent.Int64
+		}
+		flat = append(flat, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return thread(flat), nil
+}
+
+func thread(flat []Comment) []Comment {
+	children := map[int64][]Comment{}
+	for _, c := range flat {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		}
+	}
+
+	var attach func(c Comment) Comment
+	attach = func(c Comment) Comment {
+		for _, r := range children[c.ID] {
+			c.Replies = append(c.Replies, attach(r))
+		}
+		return c
+	}
+
+	roots := []Comment{}
+	for _, c := range flat {
+		if c.ParentID == nil {
+			roots = append(roots, attach(c))
+		}
+	}
+	return roots
+}
+
+// GetByStatus lists comments for moderation, newest first.
+func GetByStatus(status string, page int) ([]Comment, error) {
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT id, content_id, parent_id, name, body, status, ip, created_at
+		FROM comments
+		WHERE status = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, status, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	defer rows.Close()
+
+	list := []Comment{}
+	for rows.Next() {
+		var (
+			c      Comment
+			parent sql.NullInt64
+		)
+		if err := rows.Scan(&c.ID, &c.ContentID, &parent, &c.Name, &c.Body, &c.Status, &c.IP, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if parent.Valid {
+			c.ParentID = &parent.Int64
+		}
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
+
+// ContentID returns the ID of the content item comment id is on.
+func ContentID(id int64) (int64, error) {
+	var contentID int64
+	err := db.DB.QueryRowContext(context.Background(),
+		"SELECT content_id FROM comments WHERE id = ?", id).Scan(&contentID)
+	if err == sql.ErrNoRows {
+		return 0, ErrNotFound
+	}
+	return contentID, err
+}
+
+func SetStatus(id int64, status string) error {
+	res, err := db.DB.ExecContext(context.Background(),
+		"UPDATE comments SET status = ? WHERE id = ?", status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes a comment together with all replies below it.
+func Delete(id int64) error {
+	res, err := db.DB.ExecContext(context.Background(), `
+		WITH RECURSIVE thread(id) AS (
+			SELECT ?
+			UNION
+			SELECT c.id FROM comments c JOIN thread t ON c.parent_id = t.id
+		)
+		DELETE FROM comments WHERE id IN (SELECT id FROM thread)
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteForContentTx removes all comments of a content item within tx, in
+// which the caller deletes the content item itself.
+func DeleteForContentTx(ctx context.Context, tx *sql.Tx, contentID int64) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM comments WHERE content_id = ?", contentID)
+	return err
+}
@@ -0,0 +1,110 @@
+package content
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"example.com/portfolio/db"
+	"example.com/portfolio/validation"
+)
+
+type ArchiveMonth struct {
+	Month int `json:"month"`
+	Count int `json:"count"`
+}
+
+type ArchiveYear struct {
+	Year   int            `json:"year"`
+	Count  int            `json:"count"`
+	Months []ArchiveMonth `json:"months"`
+}
+
+// Archive counts the published items listed in a language, which falls
+// back along LanguageChain as listings do, by year and month of creation,
+// newest first. Only items of type typ count. Months without items are
+// left out.
+func Archive(language, typ string) ([]ArchiveYear, error) {
+	years, err := archiveCache.GetOrLoad(language+"/"+typ, func() ([]ArchiveYear, error) {
+		return queryArchive(language, typ)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]ArchiveYear, len(years))
+	for i, y := range years {
+		y.Months = append([]ArchiveMonth(nil), y.Months...)
+		out[i] = y
+	}
+	return out, nil
+}
+
+func queryArchive(language, typ string) ([]ArchiveYear, error) {
+	chain := fallbackChain(language)
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT CAST(strftime('%Y', created_at) AS INTEGER) AS year,
+			   CAST(strftime('%m', created_at) AS INTEGER) AS month,
+			   COUNT(*)
+		FROM blog_data
+		WHERE id IN (`+preferredIDs("type = ?", "created_at IS NOT NULL")+`)
+		GROUP BY year, month
+		ORDER BY year DESC, month DESC
+	`, chain, chain, typ)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count archive: %w", err)
+	}
+	defer rows.Close()
+
+	years := []ArchiveYear{}
+	for rows.Next() {
+		var year, month, count int
+		if err := rows.Scan(&year, &month, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan archive: %w", err)
+		}
+		if len(years) == 0 || years[len(years)-1].Year != year {
+			years = append(years, ArchiveYear{Year: year})
+		}
+		y := &years[len(years)-1]
+		y.Count += count
+		y.Months = append(y.Months, ArchiveMonth{Month: month, Count: count})
+	}
+	return years, rows.Err()
+}
+
+// DateRange turns the from and to bounds of a listing, each YYYY or
+// YYYY-MM and both inclusive, into creation times: items created at or
+// after start and before end match. An empty bound is left open.
+func DateRange(from, to string) (start, end string, err error) {
+	var first, last time.Time
+	if from != "" {
+		first, _, err = period("from", from)
+		if err != nil {
+			return "", "", err
+		}
+		start = first.Format(timeLayout)
+	}
+	if to != "" {
+		_, last, err = period("to", to)
+		if err != nil {
+			return "", "", err
+		}
+		end = last.Format(timeLayout)
+	}
+	if from != "" && to != "" && !first.Before(last) {
+		return "", "", invalidFields(validation.Errors{{Field: "from", Reason: "must not be after to"}})
+	}
+	return start, end, nil
+}
+
+// period returns the start of the year or month s names and the start of
+// the one after it.
+func period(name, s string) (time.Time, time.Time, error) {
+	if t, err := time.Parse("2006", s); err == nil {
+		return t, t.AddDate(1, 0, 0), nil
+	}
+	if t, err := time.Parse("2006-01", s); err == nil {
+		return t, t.AddDate(0, 1, 0), nil
+	}
+	return time.Time{}, time.Time{}, invalidFields(validation.Errors{{Field: name, Reason: "must be YYYY or YYYY-MM"}})
+}
@@ -3,11 +3,16 @@ package content
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"strings"
+	"log"
+	"time"
 
+	"example.com/portfolio/analytics"
+	"example.com/portfolio/comments"
 	"example.com/portfolio/db"
-	"example.com/portfolio/utils"
+	"example.com/portfolio/reactions"
+	"example.com/portfolio/validation"
 )
 
 type Content struct {
@@ -19,43 +24,122 @@ type Content struct {
 	Body      string   `json:"body"`
 	Tag       string   `json:"meta_tag,omitempty"`
 	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at,omitempty"`
 	Featured  string   `json:"featured,omitempty"`
+	Status    string   `json:"status,omitempty"`
+	Version   int64    `json:"version,omitempty"`
 	Score     *float64 `json:"score,omitempty"`
+
+	// TranslationGroup is shared by the translations of one item; it is
+	// the ID of one of them. RequestedLanguage is set when the item is
+	// served in place of a missing translation into that language.
+	TranslationGroup  int64  `json:"translation_group,omitempty"`
+	RequestedLanguage string `json:"requested_language,omitempty"`
+
+	TitleHighlight string `json:"title_highlight,omitempty"`
+	Snippet        string `json:"snippet,omitempty"`
+
+	Reactions map[string]int `json:"reactions,omitempty"`
+	Project   *Project       `json:"project,omitempty"`
+	Series    *SeriesNav     `json:"series,omitempty"`
+	Gallery   []GalleryImage `json:"gallery,omitempty"`
+
+	// attached and reactionChanges are loaded by Revision for State.
+	attached        int64
+	reactionChanges int64
+}
+
+// Filter selects the page of contents returned by GetContents. Empty
+// string fields do not filter. From and To limit the listing to items
+// created in a range of years or months; each is YYYY or YYYY-MM and
+// inclusive. Plain listings in a language fall back to other languages
+// along LanguageChain for items not translated into it; searches only
+// match the language itself.
+type Filter struct {
+	Title     string
+	Page      int
+	Language  string
+	Category  string
+	Featured  string
+	Tech      string
+	From      string
+	To        string
+	Highlight Highlight
+}
+
+// Highlight configures the markers and length of the FTS5 highlight() and
+// snippet() output attached to search results.
+type Highlight struct {
+	Open     string
+	Close    string
+	Ellipsis string
+	Tokens   int
+}
+
+func DefaultHighlight() Highlight {
+	return Highlight{Open: "<mark>", Close: "</mark>", Ellipsis: "…", Tokens: 24}
 }
 
 func (c *Content) Add() error {
-	if c.Featured == "" {
-		c.Featured = "false"
+	if err := c.Validate(); err != nil {
+		return err
 	}
-
-	imageURL, err := utils.UploadImage(c.Image)
-	if err != nil {
-		return fmt.Errorf("failed to upload image: %w", err)
+	if err := c.normalizeCreatedAt(); err != nil {
+		return err
 	}
 
 	query := `
-	INSERT INTO blog_data (language, type, image, title, body, meta_tag, created_at, featured)
-	VALUES (?, ?, ?, ?, ?, ?, datetime('now'), ?);
+	INSERT INTO blog_data (language, type, image, title, body, meta_tag, created_at, updated_at, featured, status, translation_group)
+	VALUES (?, ?, ?, ?, ?, ?, COALESCE(NULLIF(?, ''), datetime('now')), datetime('now'), ?, ?, NULLIF(?, 0));
 	`
 
-	res, err := db.DB.ExecContext(context.Background(), query,
+	ctx := context.Background()
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := c.joinGroup(ctx, tx); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, query,
 		c.Language,
 		c.Type,
-		imageURL,
+		c.Image,
 		c.Title,
 		c.Body,
 		c.Tag,
+		c.CreatedAt,
 		c.Featured,
+		c.Status,
+		c.TranslationGroup,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert content: %w", err)
 	}
 
 	id, _ := res.LastInsertId()
+	if c.Project != nil {
+		if err := saveProject(ctx, tx, id, c.Project); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
 	c.ID = id
+	c.Version = 1
+	if c.TranslationGroup == 0 {
+		c.TranslationGroup = id
+	}
+	defer invalidate()
+
+	reindexStemmed(c)
 
-	row := db.DB.QueryRowContext(context.Background(), "SELECT created_at FROM blog_data WHERE id = ?", id)
-	if err := row.Scan(&c.CreatedAt); err != nil {
+	row := db.DB.QueryRowContext(ctx, "SELECT created_at, updated_at FROM blog_data WHERE id = ?", id)
+	if err := row.Scan(&c.CreatedAt, &c.UpdatedAt); err != nil {
 		return fmt.Errorf("could not get created_at: %w", err)
 	}
 
@@ -63,148 +147,477 @@ func (c *Content) Add() error {
 }
 
 func (c *Content) Update() error {
-	imagePath := c.Image
-	if !strings.HasPrefix(imagePath, "http") {
-		var err error
-		imagePath, err = utils.UploadImage(imagePath)
-		if err != nil {
-			return fmt.Errorf("failed to upload image: %w", err)
-		}
+	stored, err := storedFields(c.ID)
+	if err != nil {
+		return err
+	}
+	if err := c.validateUpdate(stored); err != nil {
+		return err
+	}
+	if err := c.normalizeCreatedAt(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := c.joinGroup(ctx, tx); err != nil {
+		return err
 	}
 
 	query := `
 	UPDATE blog_data
-	SET image = ?, title = ?, body = ?, meta_tag = ?, featured = ?
-	WHERE id = ?;
+	SET language = ?, type = ?, image = ?, title = ?, body = ?, meta_tag = ?, featured = ?, status = ?,
+		translation_group = NULLIF(?, id), created_at = COALESCE(NULLIF(?, ''), created_at),
+		version = version + 1, updated_at = datetime('now')
+	WHERE id = ? AND version = ?;
 	`
-	_, err := db.DB.ExecContext(context.Background(), query,
-		imagePath, c.Title, c.Body, c.Tag, c.Featured, c.ID)
+	res, err := tx.ExecContext(ctx, query,
+		c.Language, c.Type, c.Image, c.Title, c.Body, c.Tag, c.Featured, c.Status, c.TranslationGroup, c.CreatedAt,
+		c.ID, c.Version)
 	if err != nil {
 		return fmt.Errorf("failed to update blog_data: %w", err)
 	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrVersionConflict
+	}
 
-	_, _ = db.DB.ExecContext(context.Background(),
+	// Translations grouped under c's ID follow it into another group.
+	if c.TranslationGroup != c.ID {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE blog_data
+			SET translation_group = ?, attached_version = attached_version + 1, updated_at = datetime('now')
+			WHERE translation_group = ? AND id != ?
+		`, c.TranslationGroup, c.ID, c.ID)
+		if err != nil {
+			return fmt.Errorf("failed to move translations: %w", err)
+		}
+	}
+
+	if err := saveProject(ctx, tx, c.ID, c.Project); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	c.Version++
+	defer invalidate()
+	_ = db.DB.QueryRowContext(ctx,
+		"SELECT updated_at FROM blog_data WHERE id = ?", c.ID).Scan(&c.UpdatedAt)
+
+	_, _ = db.DB.ExecContext(ctx,
 		`INSERT INTO blog_search(blog_search, rowid, title, body)
 		 VALUES('delete', ?, '', '');`, c.ID)
-	_, _ = db.DB.ExecContext(context.Background(),
+	_, _ = db.DB.ExecContext(ctx,
 		`INSERT INTO blog_search(rowid, title, body) VALUES (?, ?, ?)`,
 
 		c.ID, c.Title, c.Body)
+	reindexStemmed(c)
+
+	// The title and status show in the series navigation of the others.
+	touch(seriesSiblings(c.ID)...)
 
 	return nil
 }
 
+// storedFields returns the validated fields of content id as stored, or a
+// zero Content if there is no such row.
+func storedFields(id int64) (Content, error) {
+	var c Content
+	err := db.DB.QueryRowContext(context.Background(), `
+		SELECT id, language, type, image, title, body, meta_tag, featured, status
+		FROM blog_data WHERE id = ?
+	`, id).Scan(&c.ID, &c.Language, &c.Type, &c.Image, &c.Title, &c.Body, &c.Tag, &c.Featured, &c.Status)
+	if err != nil && err != sql.ErrNoRows {
+		return Content{}, fmt.Errorf("failed to get content: %w", err)
+	}
+	return c, nil
+}
+
+// normalizeCreatedAt checks a provided creation time, e.g. from an
+// import, which Add and Update keep. Empty leaves it to the database.
+func (c *Content) normalizeCreatedAt() error {
+	if c.CreatedAt == "" {
+		return nil
+	}
+	t, err := time.Parse(timeLayout, c.CreatedAt)
+	if err != nil {
+		return invalidFields(validation.Errors{{Field: "created_at", Reason: "must be formatted as " + timeLayout}})
+	}
+	c.CreatedAt = t.Format(timeLayout)
+	return nil
+}
+
+// Delete removes c, if it is still at c.Version, together with its
+// comments and reactions.
 func (c *Content) Delete() error {
-	_, err := db.DB.ExecContext(context.Background(),
-		"DELETE FROM blog_data WHERE id = ?", c.ID)
+	siblings := seriesSiblings(c.ID)
+	ctx := context.Background()
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		"DELETE FROM blog_data WHERE id = ? AND version = ?", c.ID, c.Version)
 	if err != nil {
 		return fmt.Errorf("failed to delete content: %w", err)
 	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrVersionConflict
+	}
+	if err := comments.DeleteForContentTx(ctx, tx, c.ID); err != nil {
+		return fmt.Errorf("failed to delete comments: %w", err)
+	}
+	if err := reactions.DeleteForContentTx(ctx, tx, c.ID); err != nil {
+		return fmt.Errorf("failed to delete reactions: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	defer invalidate()
 	_, _ = db.DB.ExecContext(context.Background(),
 		`INSERT INTO blog_search(blog_search, rowid, title, body)
 		 VALUES('delete', ?, '', '');`, c.ID)
+	if _, err := db.DB.ExecContext(context.Background(),
+		"DELETE FROM blog_search_stem WHERE rowid = ?", c.ID); err != nil {
+		log.Printf("⚠️ Could not remove content %d from blog_search_stem: %v", c.ID, err)
+	}
+	_ = saveProject(ctx, db.DB, c.ID, nil)
+	_, _ = db.DB.ExecContext(context.Background(),
+		"DELETE FROM series_items WHERE content_id = ?", c.ID)
+	_, _ = db.DB.ExecContext(context.Background(),
+		"DELETE FROM content_images WHERE content_id = ?", c.ID)
+	touch(siblings...)
 	return nil
 }
 
+// ErrVersionConflict is returned by Update and Delete when the row has
+// been changed since c was loaded, i.e. its version no longer matches.
+var ErrVersionConflict = errors.New("content was modified concurrently")
+
+// ETag identifies the stored revision of the content.
+func (c Content) ETag() string {
+	return fmt.Sprintf(`"%d-%d"`, c.ID, c.Version)
+}
+
+// GetById returns a content item with everything attached to it. All
+// but the reaction counts, which change far more often than the content,
+// come from the read cache when possible.
 func GetById(id int64) (Content, error) {
+	c, err := byIDCache.GetOrLoad(id, func() (Content, error) { return loadById(id) })
+	if err != nil {
+		return Content{}, err
+	}
+
+	list := []Content{c.clone()}
+	if err := attachReactions(list); err != nil {
+		return Content{}, err
+	}
+	return list[0], nil
+}
+
+func loadById(id int64) (Content, error) {
 	query := `
-	SELECT id, language, type, image, title, body, meta_tag, created_at, featured
+	SELECT id, language, type, image, title, body, meta_tag, created_at, COALESCE(updated_at, created_at), featured, status, version,
+		COALESCE(translation_group, id)
 	FROM blog_data WHERE id = ?;
 	`
 	row := db.DB.QueryRowContext(context.Background(), query, id)
 
 	var c Content
-	if err := row.Scan(&c.ID, &c.Language, &c.Type, &c.Image, &c.Title, &c.Body, &c.Tag, &c.CreatedAt, &c.Featured); err != nil {
+	if err := row.Scan(&c.ID, &c.Language, &c.Type, &c.Image, &c.Title, &c.Body, &c.Tag, &c.CreatedAt, &c.UpdatedAt, &c.Featured, &c.Status, &c.Version, &c.TranslationGroup); err != nil {
 		if err == sql.ErrNoRows {
 			return Content{}, fmt.Errorf("blog not found")
 		}
 		return Content{}, fmt.Errorf("failed to get content: %w", err)
 	}
 
-	return c, nil
+	list := []Content{c}
+	if err := attachProjects(list); err != nil {
+		return Content{}, err
+	}
+
+	series, err := seriesNav(c.ID)
+	if err != nil {
+		return Content{}, err
+	}
+	list[0].Series = series
+
+	list[0].Gallery, err = GetGallery(c.ID)
+	if err != nil {
+		return Content{}, err
+	}
+
+	return list[0], nil
 }
 
-func GetContents(title string, page int, language string, category string, featured string) ([]Content, error) {
-	const limit = 10
-	offset := (page - 1) * limit
+// FindByTitle returns the ID of the content with exactly this language,
+// type and title, or 0 if there is none.
+func FindByTitle(language, typ, title string) (int64, error) {
+	var id int64
+	err := db.DB.QueryRowContext(context.Background(),
+		"SELECT id FROM blog_data WHERE language = ? AND type = ? AND title = ? ORDER BY id LIMIT 1",
+		language, typ, title).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find content: %w", err)
+	}
+	return id, nil
+}
+
+// decorate loads the data kept outside blog_data for a list of contents.
+func decorate(contents []Content) error {
+	if err := attachReactions(contents); err != nil {
+		return err
+	}
+	return attachProjects(contents)
+}
+
+// attachReactions fills in the reaction counts of a list of contents with
+// a single query.
+func attachReactions(contents []Content) error {
+	ids := make([]int64, len(contents))
+	for i, c := range contents {
+		ids[i] = c.ID
+	}
+	counts, err := reactions.Counts(ids)
+	if err != nil {
+		return err
+	}
+	for i := range contents {
+		contents[i].Reactions = counts[contents[i].ID]
+	}
+	return nil
+}
 
+// GetContents returns one page of contents selected by f. Pages are kept
+// in the read cache, keyed by the whole filter; reaction counts are
+// attached afterwards.
+func GetContents(f Filter) ([]Content, error) {
 	var (
-		rows *sql.Rows
-		err  error
+		q   *SearchQuery
+		err error
 	)
+	if f.Title != "" {
+		q, err = ParseQuery(f.Title)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	if title != "" {
-		match := title + "*"
+	page, err := listCache.GetOrLoad(fmt.Sprintf("%+v", f), func() (contentPage, error) {
+		return queryContents(f, q)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if q != nil && f.Page == 1 {
+		analytics.LogSearch(f.Title, f.Language, f.Category, page.total)
+	}
+
+	if len(page.contents) == 0 {
+		return nil, fmt.Errorf("no contents found")
+	}
+
+	contents := cloneAll(page.contents)
+	if err := attachReactions(contents); err != nil {
+		return nil, err
+	}
+
+	return contents, nil
+}
+
+// contentPage is one page of a listing. For searches, total is the number
+// of matches on all pages; it is not counted for plain listings.
+type contentPage struct {
+	contents []Content
+	total    int
+}
+
+func queryContents(f Filter, q *SearchQuery) (contentPage, error) {
+	const limit = 10
+	offset := (f.Page - 1) * limit
+	title, language, category, featured, hl := f.Title, f.Language, f.Category, f.Featured, f.Highlight
+	start, end, err := DateRange(f.From, f.To)
+	if err != nil {
+		return contentPage{}, err
+	}
+
+	var rows *sql.Rows
+
+	stemmed := q != nil && isStemmed(language)
+	stem := func(s string) []string { return stemTokens(language, s) }
+	var stems []string
+
+	if stemmed {
+		var match string
+		match, err = q.fts(stem)
+		if err != nil {
+			return contentPage{}, err
+		}
+		stems = q.stems(stem)
 
 		query := `
 			SELECT d.id, d.language, d.type, d.image, d.title, d.body,
-				   d.created_at, d.featured, bm25(blog_search) AS score
+				   d.created_at, d.featured, bm25(blog_search_stem) AS score,
+				   COUNT(*) OVER () AS total
+			FROM blog_search_stem
+			JOIN blog_data d ON d.id = blog_search_stem.rowid
+			WHERE blog_search_stem MATCH ?
+			  AND d.language = ?
+			  AND d.status = 'published'
+			  AND (? = '' OR d.type = ?)
+			  AND (? = '' OR d.featured = ?)
+			  AND (? = '' OR d.id IN (SELECT content_id FROM project_data
+			                          WHERE instr(',' || lower(tech_stack) || ',', ',' || lower(?) || ',') > 0))
+			  AND (? = '' OR d.created_at >= ?)
+			  AND (? = '' OR d.created_at < ?)
+			ORDER BY score ASC
+			LIMIT ? OFFSET ?;
+		`
+
+		rows, err = db.DB.QueryContext(context.Background(),
+			query,
+			match,
+			language,
+			category, category,
+			featured, featured,
+			f.Tech, f.Tech,
+			start, start,
+			end, end,
+			limit, offset)
+	} else if q != nil {
+		var match string
+		match, err = q.fts(nil)
+		if err != nil {
+			return contentPage{}, err
+		}
+
+		query := `
+			SELECT d.id, d.language, d.type, d.image, d.title, d.body,
+				   d.created_at, d.featured, bm25(blog_search) AS score,
+				   highlight(blog_search, 0, ?, ?),
+				   snippet(blog_search, 1, ?, ?, ?, ?),
+				   COUNT(*) OVER () AS total
 			FROM blog_search
 			JOIN blog_data d ON d.id = blog_search.rowid
 			WHERE blog_search MATCH ?
+			  AND d.status = 'published'
 			  AND (? = '' OR d.language = ?)
 			  AND (? = '' OR d.type = ?)
 			  AND (? = '' OR d.featured = ?)
+			  AND (? = '' OR d.id IN (SELECT content_id FROM project_data
+			                          WHERE instr(',' || lower(tech_stack) || ',', ',' || lower(?) || ',') > 0))
+			  AND (? = '' OR d.created_at >= ?)
+			  AND (? = '' OR d.created_at < ?)
 			ORDER BY score ASC
 			LIMIT ? OFFSET ?;
 		`
 
 		rows, err = db.DB.QueryContext(context.Background(),
 			query,
+			hl.Open, hl.Close,
+			hl.Open, hl.Close, hl.Ellipsis, hl.Tokens,
 			match,
 			language, language,
 			category, category,
 			featured, featured,
+			f.Tech, f.Tech,
+			start, start,
+			end, end,
 			limit, offset)
 	} else {
+		// The filters go inside the window, so that an item whose
+		// preferred translation is filtered out is listed in the next
+		// language of the chain that matches. Without a language every
+		// item is its own partition.
 		query := `
 			SELECT id, language, type, image, title, body, created_at, featured
 			FROM blog_data
-			WHERE (? = '' OR language = ?)
-			  AND (? = '' OR type = ?)
-			  AND (? = '' OR featured = ?)
+			WHERE id IN (
+			    SELECT id FROM (
+			        SELECT id, ROW_NUMBER() OVER (
+			            PARTITION BY CASE WHEN ? = '' THEN id ELSE COALESCE(translation_group, id) END
+			            ORDER BY instr(?, ',' || language || ','), id
+			        ) AS preference
+			        FROM blog_data
+			        WHERE status = 'published'
+			          AND (? = '' OR instr(?, ',' || language || ',') > 0)
+			          AND (? = '' OR type = ?)
+			          AND (? = '' OR featured = ?)
+			          AND (? = '' OR id IN (SELECT content_id FROM project_data
+			                                WHERE instr(',' || lower(tech_stack) || ',', ',' || lower(?) || ',') > 0))
+			          AND (? = '' OR created_at >= ?)
+			          AND (? = '' OR created_at < ?)
+			    ) WHERE preference = 1)
 			ORDER BY created_at DESC
 			LIMIT ? OFFSET ?;
 		`
 
+		chain := fallbackChain(language)
 		rows, err = db.DB.QueryContext(context.Background(),
 			query,
-			language, language,
+			chain, chain,
+			chain, chain,
 			category, category,
 			featured, featured,
+			f.Tech, f.Tech,
+			start, start,
+			end, end,
 			limit, offset)
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute SQL: %w", err)
+		return contentPage{}, fmt.Errorf("failed to execute SQL: %w", err)
 	}
 	defer rows.Close()
 
-	var contents []Content
+	var (
+		contents []Content
+		total    int
+	)
 	for rows.Next() {
 		var c Content
-		if title != "" {
+		if stemmed {
 			err = rows.Scan(&c.ID, &c.Language, &c.Type, &c.Image, &c.Title,
-				&c.Body, &c.CreatedAt, &c.Featured, &c.Score)
+				&c.Body, &c.CreatedAt, &c.Featured, &c.Score, &total)
+			c.TitleHighlight = stemmedHighlight(language, c.Title, stems, hl, false)
+			c.Snippet = stemmedHighlight(language, c.Body, stems, hl, true)
+		} else if title != "" {
+			err = rows.Scan(&c.ID, &c.Language, &c.Type, &c.Image, &c.Title,
+				&c.Body, &c.CreatedAt, &c.Featured, &c.Score, &c.TitleHighlight, &c.Snippet, &total)
 		} else {
 			err = rows.Scan(&c.ID, &c.Language, &c.Type, &c.Image, &c.Title,
 				&c.Body, &c.CreatedAt, &c.Featured)
 			c.Score = nil
+			if language != "" && c.Language != language {
+				c.RequestedLanguage = language
+			}
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+			return contentPage{}, fmt.Errorf("failed to scan row: %w", err)
 		}
 
 		contents = append(contents, c)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return contentPage{}, err
 	}
 
-	if len(contents) == 0 {
-		return nil, fmt.Errorf("no contents found")
+	if err := attachProjects(contents); err != nil {
+		return contentPage{}, err
 	}
 
-	return contents, nil
+	return contentPage{contents: contents, total: total}, nil
 }
@@ -0,0 +1,240 @@
+package content
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"example.com/portfolio/comments"
+	"example.com/portfolio/db"
+	"example.com/portfolio/reactions"
+)
+
+const (
+	BulkDelete      = "delete"
+	BulkSetFeatured = "set_featured"
+	BulkSetStatus   = "set_status"
+	BulkAddTag      = "add_tag"
+	BulkRemoveTag   = "remove_tag"
+
+	bulkMaxIDs = 200
+	bulkMaxOps = 10
+)
+
+// ErrBulkRejected is returned by Bulk when an operation cannot be applied
+// to one of the items; nothing is changed in that case.
+var ErrBulkRejected = errors.New("bulk operation rejected")
+
+type BulkOp struct {
+	Op    string `json:"op"`
+	Value string `json:"value"`
+}
+
+type BulkResult struct {
+	ID      int64  `json:"id"`
+	Status  string `json:"status"`
+	Changed bool   `json:"changed"`
+	Error   string `json:"error,omitempty"`
+}
+
+func validateBulk(ids []int64, ops []BulkOp) error {
+	if len(ids) == 0 || len(ids) > bulkMaxIDs {
+		return fmt.Errorf("%w: ids must list between 1 and %d content IDs", ErrInvalid, bulkMaxIDs)
+	}
+	if len(ops) == 0 || len(ops) > bulkMaxOps {
+		return fmt.Errorf("%w: operations must list between 1 and %d operations", ErrInvalid, bulkMaxOps)
+	}
+
+	for i := range ops {
+		op := &ops[i]
+		op.Value = strings.TrimSpace(op.Value)
+		switch op.Op {
+		case BulkDelete:
+			if len(ops) > 1 {
+				return fmt.Errorf("%w: delete cannot be combined with other operations", ErrInvalid)
+			}
+		case BulkSetFeatured:
+			if op.Value != "true" && op.Value != "false" {
+				return fmt.Errorf("%w: set_featured value must be true or false", ErrInvalid)
+			}
+		case BulkSetStatus:
+			if !contains(Statuses, op.Value) {
+				return fmt.Errorf("%w: set_status value must be one of %s", ErrInvalid, strings.Join(Statuses, ", "))
+			}
+		case BulkAddTag, BulkRemoveTag:
+			if op.Value == "" || strings.Contains(op.Value, ",") || len([]rune(op.Value)) > 50 {
+				return fmt.Errorf("%w: %s value must be a single tag of at most 50 characters", ErrInvalid, op.Op)
+			}
+		default:
+			return fmt.Errorf("%w: unknown operation %q", ErrInvalid, op.Op)
+		}
+	}
+	return nil
+}
+
+// Bulk applies ops, in order, to every content item in ids within one
+// transaction. If any item is missing or an operation fails, the whole
+// batch is rolled back and ErrBulkRejected is returned together with the
+// per-item results explaining why.
+func Bulk(ids []int64, ops []BulkOp) ([]BulkResult, error) {
+	if err := validateBulk(ids, ops); err != nil {
+		return nil, err
+	}
+
+	// Status changes and deletes also change the series navigation of
+	// the other items in a series, which are looked up before a delete
+	// removes them from it.
+	siblings := map[int64][]int64{}
+	for _, id := range ids {
+		siblings[id] = seriesSiblings(id)
+	}
+
+	ctx := context.Background()
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	results := make([]BulkResult, 0, len(ids))
+	failed := false
+	seen := map[int64]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		r := BulkResult{ID: id, Status: "ok"}
+		changed, err := bulkApply(ctx, tx, id, ops)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			r.Status, r.Error = "failed", "content not found"
+			failed = true
+		case err != nil:
+			r.Status, r.Error = "failed", err.Error()
+			failed = true
+		default:
+			r.Changed = changed
+		}
+		results = append(results, r)
+	}
+
+	if failed {
+		for i := range results {
+			if results[i].Status == "ok" {
+				results[i].Status, results[i].Changed = "rolled_back", false
+			}
+		}
+		return results, ErrBulkRejected
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	invalidate()
+	for _, r := range results {
+		if r.Changed {
+			touch(siblings[r.ID]...)
+		}
+	}
+	return results, nil
+}
+
+func bulkApply(ctx context.Context, tx *sql.Tx, id int64, ops []BulkOp) (bool, error) {
+	var featured, status, tags string
+	err := tx.QueryRowContext(ctx,
+		"SELECT COALESCE(featured, 'false'), status, COALESCE(meta_tag, '') FROM blog_data WHERE id = ?", id).
+		Scan(&featured, &status, &tags)
+	if err != nil {
+		return false, err
+	}
+
+	if ops[0].Op == BulkDelete {
+		return true, bulkDelete(ctx, tx, id)
+	}
+
+	newFeatured, newStatus, newTags := featured, status, tags
+	for _, op := range ops {
+		switch op.Op {
+		case BulkSetFeatured:
+			newFeatured = op.Value
+		case BulkSetStatus:
+			newStatus = op.Value
+		case BulkAddTag:
+			newTags = addTag(newTags, op.Value)
+		case BulkRemoveTag:
+			newTags = removeTag(newTags, op.Value)
+		}
+	}
+	if len([]rune(newTags)) > 500 {
+		return false, fmt.Errorf("meta_tag would exceed 500 characters")
+	}
+	if newFeatured == featured && newStatus == status && newTags == tags {
+		return false, nil
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE blog_data
+		SET featured = ?, status = ?, meta_tag = ?,
+			version = version + 1, updated_at = datetime('now')
+		WHERE id = ?
+	`, newFeatured, newStatus, newTags, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to update content: %w", err)
+	}
+	return true, nil
+}
+
+// bulkDelete removes a content item and everything attached to it,
+// including its comments and reactions.
+func bulkDelete(ctx context.Context, tx *sql.Tx, id int64) error {
+	for _, query := range []string{
+		"DELETE FROM blog_data WHERE id = ?",
+		"DELETE FROM blog_search_stem WHERE rowid = ?",
+		"DELETE FROM project_data WHERE content_id = ?",
+		"DELETE FROM series_items WHERE content_id = ?",
+		"DELETE FROM content_images WHERE content_id = ?",
+	} {
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return fmt.Errorf("failed to delete content: %w", err)
+		}
+	}
+	if err := comments.DeleteForContentTx(ctx, tx, id); err != nil {
+		return fmt.Errorf("failed to delete comments: %w", err)
+	}
+	if err := reactions.DeleteForContentTx(ctx, tx, id); err != nil {
+		return fmt.Errorf("failed to delete reactions: %w", err)
+	}
+	return nil
+}
+
+// addTag appends tag to a comma-separated tag list unless it is already
+// there, ignoring case.
+func addTag(tags, tag string) string {
+	if tagOverlap([]string{strings.ToLower(tag)}, splitTags(tags)) > 0 {
+		return tags
+	}
+	if strings.TrimSpace(tags) == "" {
+		return tag
+	}
+	return strings.TrimRight(strings.TrimSpace(tags), ",") + ", " + tag
+}
+
+func removeTag(tags, tag string) string {
+	if tagOverlap([]string{strings.ToLower(tag)}, splitTags(tags)) == 0 {
+		return tags
+	}
+
+	var kept []string
+	for _, t := range strings.Split(tags, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" || strings.EqualFold(t, tag) {
+			continue
+		}
+		kept = append(kept, t)
+	}
+	return strings.Join(kept, ", ")
+}
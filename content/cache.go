@@ -0,0 +1,102 @@
+package content
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"example.com/portfolio/cache"
+)
+
+// Content changes a few times a week, so reads are served from memory.
+// Every write in this package purges the caches; the TTL bounds how long
+// writes made by other processes, such as the import command, go unseen.
+var (
+	byIDCache    = cache.New[int64, Content](512, 10*time.Minute)
+	listCache    = cache.New[string, contentPage](256, 5*time.Minute)
+	archiveCache = cache.New[string, []ArchiveYear](16, 10*time.Minute)
+	// stateCache holds the summary of blog_data that ListState derives
+	// list validators from; it is purged with the lists.
+	stateCache = cache.New[struct{}, contentState](1, 5*time.Minute)
+)
+
+// ConfigureCache disables the read cache when CONTENT_CACHE is "off".
+func ConfigureCache() {
+	if strings.EqualFold(os.Getenv("CONTENT_CACHE"), "off") {
+		SetCacheEnabled(false)
+	}
+}
+
+func SetCacheEnabled(enabled bool) {
+	byIDCache.SetEnabled(enabled)
+	listCache.SetEnabled(enabled)
+	archiveCache.SetEnabled(enabled)
+	stateCache.SetEnabled(enabled)
+}
+
+func PurgeCache() {
+	byIDCache.Purge()
+	listCache.Purge()
+	archiveCache.Purge()
+	stateCache.Purge()
+}
+
+func CacheStats() map[string]cache.Stats {
+	return map[string]cache.Stats{
+		"by_id":   byIDCache.Stats(),
+		"lists":   listCache.Stats(),
+		"archive": archiveCache.Stats(),
+		"state":   stateCache.Stats(),
+	}
+}
+
+// invalidate is called after every write to content or to the data
+// attached to it.
+func invalidate() {
+	PurgeCache()
+}
+
+// clone returns a copy of c that shares nothing mutable with it, so that
+// callers can modify what they get from the cache.
+func (c Content) clone() Content {
+	if c.Score != nil {
+		score := *c.Score
+		c.Score = &score
+	}
+	if c.Reactions != nil {
+		reactions := make(map[string]int, len(c.Reactions))
+		for k, v := range c.Reactions {
+			reactions[k] = v
+		}
+		c.Reactions = reactions
+	}
+	if c.Project != nil {
+		p := *c.Project
+		p.TechStack = append([]string(nil), p.TechStack...)
+		c.Project = &p
+	}
+	if c.Series != nil {
+		s := *c.Series
+		if s.Prev != nil {
+			prev := *s.Prev
+			s.Prev = &prev
+		}
+		if s.Next != nil {
+			next := *s.Next
+			s.Next = &next
+		}
+		c.Series = &s
+	}
+	if c.Gallery != nil {
+		c.Gallery = append([]GalleryImage(nil), c.Gallery...)
+	}
+	return c
+}
+
+func cloneAll(contents []Content) []Content {
+	out := make([]Content, len(contents))
+	for i, c := range contents {
+		out[i] = c.clone()
+	}
+	return out
+}
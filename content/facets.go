@@ -0,0 +1,261 @@
+package content
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"example.com/portfolio/analytics"
+	"example.com/portfolio/db"
+)
+
+const (
+	facetMatchLimit = 1000
+	facetTagLimit   = 20
+	searchPageSize  = 10
+)
+
+// SearchFilter narrows a faceted search. Empty fields do not filter.
+type SearchFilter struct {
+	Language string
+	Type     string
+	Featured string
+	Tag      string
+}
+
+type Facets struct {
+	Language map[string]int `json:"language"`
+	Type     map[string]int `json:"type"`
+	Featured map[string]int `json:"featured"`
+	Tag      map[string]int `json:"tag"`
+}
+
+type SearchResult struct {
+	Total    int       `json:"total"`
+	Page     int       `json:"page"`
+	Contents []Content `json:"contents"`
+	Facets   Facets    `json:"facets"`
+}
+
+// Search runs title against every language at once and returns one page of
+// the matches that pass f, together with facet counts. Each facet is
+// counted with all filters applied except its own, so the counts tell the
+// user what selecting another value would yield.
+func Search(title string, f SearchFilter, page int, hl Highlight) (SearchResult, error) {
+	q, err := ParseQuery(title)
+	if err != nil {
+		return SearchResult{}, err
+	}
+
+	matches, err := searchAll(q, hl)
+	if err != nil {
+		return SearchResult{}, err
+	}
+
+	res := SearchResult{
+		Page:     page,
+		Contents: []Content{},
+		Facets: Facets{
+			Language: map[string]int{},
+			Type:     map[string]int{},
+			Featured: map[string]int{},
+			Tag:      map[string]int{},
+		},
+	}
+
+	var filtered []Content
+	for _, c := range matches {
+		tags := splitTags(c.Tag)
+		lang := f.Language == "" || c.Language == f.Language
+		typ := f.Type == "" || c.Type == f.Type
+		feat := f.Featured == "" || c.Featured == f.Featured
+		tag := f.Tag == "" || tagOverlap([]string{f.Tag}, tags) > 0
+
+		if typ && feat && tag {
+			res.Facets.Language[c.Language]++
+		}
+		if lang && feat && tag {
+			res.Facets.Type[c.Type]++
+		}
+		if lang && typ && tag {
+			res.Facets.Featured[c.Featured]++
+		}
+		if lang && typ && feat {
+			for _, t := range tags {
+				res.Facets.Tag[t]++
+			}
+		}
+		if lang && typ && feat && tag {
+			filtered = append(filtered, c)
+		}
+	}
+	res.Facets.Tag = topTags(res.Facets.Tag, facetTagLimit)
+
+	res.Total = len(filtered)
+	if page == 1 {
+		analytics.LogSearch(title, f.Language, f.Type, res.Total)
+	}
+	start := (page - 1) * searchPageSize
+	if start < len(filtered) {
+		end := start + searchPageSize
+		if end > len(filtered) {
+			end = len(filtered)
+		}
+		res.Contents = filtered[start:end]
+		if err := decorate(res.Contents); err != nil {
+			return SearchResult{}, err
+		}
+	}
+
+	return res, nil
+}
+
+// searchAll collects the matches of q in blog_search and, for the stemmed
+// languages, in blog_search_stem, best score first. Scores are scaled per
+// query, see scaleScores.
+func searchAll(q *SearchQuery, hl Highlight) ([]Content, error) {
+	byID := map[int64]Content{}
+	var plain []Content
+
+	query := `
+		SELECT d.id, d.language, d.type, d.image, d.title, d.body, d.meta_tag,
+			   d.created_at, d.featured, bm25(blog_search) AS score,
+			   highlight(blog_search, 0, ?, ?),
+			   snippet(blog_search, 1, ?, ?, ?, ?)
+		FROM blog_search
+		JOIN blog_data d ON d.id = blog_search.rowid
+		WHERE blog_search MATCH ?
+		  AND d.status = 'published'
+		ORDER BY score ASC
+		LIMIT ?;
+	`
+	match, err := q.fts(nil)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.DB.QueryContext(context.Background(), query,
+		hl.Open, hl.Close,
+		hl.Open, hl.Close, hl.Ellipsis, hl.Tokens,
+		match, facetMatchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	for rows.Next() {
+		var c Content
+		if err := rows.Scan(&c.ID, &c.Language, &c.Type, &c.Image, &c.Title, &c.Body, &c.Tag,
+			&c.CreatedAt, &c.Featured, &c.Score, &c.TitleHighlight, &c.Snippet); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		plain = append(plain, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	scaleScores(plain)
+	for _, c := range plain {
+		byID[c.ID] = c
+	}
+
+	for language := range stemmedLanguages {
+		stem := func(s string) []string { return stemTokens(language, s) }
+		stems := q.stems(stem)
+		var stemmed []Content
+		match, err := q.fts(stem)
+		if err != nil {
+			// Nothing is left of the query in this language; the
+			// unstemmed matches above still count.
+			continue
+		}
+
+		query := `
+			SELECT d.id, d.language, d.type, d.image, d.title, d.body, d.meta_tag,
+				   d.created_at, d.featured, bm25(blog_search_stem) AS score
+			FROM blog_search_stem
+			JOIN blog_data d ON d.id = blog_search_stem.rowid
+			WHERE blog_search_stem MATCH ?
+			  AND d.language = ?
+			  AND d.status = 'published'
+			ORDER BY score ASC
+			LIMIT ?;
+		`
+		rows, err := db.DB.QueryContext(context.Background(), query,
+			match, language, facetMatchLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute SQL: %w", err)
+		}
+		for rows.Next() {
+			var c Content
+			if err := rows.Scan(&c.ID, &c.Language, &c.Type, &c.Image, &c.Title, &c.Body, &c.Tag,
+				&c.CreatedAt, &c.Featured, &c.Score); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan row: %w", err)
+			}
+			c.TitleHighlight = stemmedHighlight(language, c.Title, stems, hl, false)
+			c.Snippet = stemmedHighlight(language, c.Body, stems, hl, true)
+			stemmed = append(stemmed, c)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		scaleScores(stemmed)
+		for _, c := range stemmed {
+			if prev, ok := byID[c.ID]; ok && *prev.Score <= *c.Score {
+				continue
+			}
+			byID[c.ID] = c
+		}
+	}
+
+	matches := make([]Content, 0, len(byID))
+	for _, c := range byID {
+		matches = append(matches, c)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if *matches[i].Score != *matches[j].Score {
+			return *matches[i].Score < *matches[j].Score
+		}
+		return matches[i].ID > matches[j].ID
+	})
+	return matches, nil
+}
+
+// scaleScores divides the bm25 scores of one query's matches, which are
+// negative and best first, by the best of them. bm25 depends on the
+// statistics of the table searched, so blog_search and blog_search_stem
+// scores are only comparable once each is relative to its own best match:
+// the result runs from -1 for the best match towards 0.
+func scaleScores(matches []Content) {
+	if len(matches) == 0 || *matches[0].Score == 0 {
+		return
+	}
+	best := *matches[0].Score
+	for i := range matches {
+		score := -*matches[i].Score / best
+		matches[i].Score = &score
+	}
+}
+
+func topTags(counts map[string]int, limit int) map[string]int {
+	if len(counts) <= limit {
+		return counts
+	}
+	tags := make([]string, 0, len(counts))
+	for t := range counts {
+		tags = append(tags, t)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if counts[tags[i]] != counts[tags[j]] {
+			return counts[tags[i]] > counts[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	top := make(map[string]int, limit)
+	for _, t := range tags[:limit] {
+		top[t] = counts[t]
+	}
+	return top
+}
@@ -0,0 +1,200 @@
+package content
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"example.com/portfolio/db"
+	"example.com/portfolio/utils"
+	"example.com/portfolio/validation"
+)
+
+var ErrImageNotFound = errors.New("image not found")
+
+// GalleryImage is one image in the ordered gallery of a content item. The
+// content's own Image stays its cover and is not part of the gallery.
+type GalleryImage struct {
+	ID        int64  `json:"id"`
+	ContentID int64  `json:"content_id"`
+	URL       string `json:"url"`
+	Alt       string `json:"alt"`
+	Caption   string `json:"caption,omitempty"`
+	Position  int    `json:"position"`
+}
+
+func validateImageText(alt, caption string) error {
+	var errs validation.Errors
+	errs.MaxLength("alt", alt, 250)
+	errs.MaxLength("caption", caption, 500)
+	return invalidFields(errs)
+}
+
+// Validate trims the alt text and caption of g and checks their length.
+func (g *GalleryImage) Validate() error {
+	g.Alt = strings.TrimSpace(g.Alt)
+	g.Caption = strings.TrimSpace(g.Caption)
+	return validateImageText(g.Alt, g.Caption)
+}
+
+// Add uploads the local file at g.URL and appends it to the end of the
+// gallery of g.ContentID.
+func (g *GalleryImage) Add() error {
+	if err := g.Validate(); err != nil {
+		return err
+	}
+
+	imageURL, err := utils.UploadImage(g.URL)
+	if err != nil {
+		return fmt.Errorf("failed to upload image: %w", err)
+	}
+	g.URL = imageURL
+
+	// The position is computed by the INSERT itself, so that concurrent
+	// uploads to the same gallery do not get the same one.
+	res, err := db.DB.ExecContext(context.Background(), `
+		INSERT INTO content_images (content_id, url, alt, caption, position)
+		SELECT ?, ?, ?, ?, COALESCE(MAX(position), 0) + 1
+		FROM content_images WHERE content_id = ?
+	`, g.ContentID, g.URL, g.Alt, g.Caption, g.ContentID)
+	if err != nil {
+		return fmt.Errorf("failed to insert image: %w", err)
+	}
+	g.ID, _ = res.LastInsertId()
+
+	err = db.DB.QueryRowContext(context.Background(),
+		"SELECT position FROM content_images WHERE id = ?", g.ID).Scan(&g.Position)
+	if err != nil {
+		return fmt.Errorf("failed to get gallery position: %w", err)
+	}
+	touch(g.ContentID)
+	return nil
+}
+
+// GetGallery returns the gallery of a content item in display order.
+func GetGallery(contentID int64) ([]GalleryImage, error) {
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT id, content_id, url, alt, caption, position
+		FROM content_images
+		WHERE content_id = ?
+		ORDER BY position ASC, id ASC
+	`, contentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	defer rows.Close()
+
+	images := []GalleryImage{}
+	for rows.Next() {
+		var g GalleryImage
+		if err := rows.Scan(&g.ID, &g.ContentID, &g.URL, &g.Alt, &g.Caption, &g.Position); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		images = append(images, g)
+	}
+	return images, rows.Err()
+}
+
+// publishedGalleries is GetGallery for every published item, by content
+// ID, loaded with a single query. Items without images are absent.
+func publishedGalleries() (map[int64][]GalleryImage, error) {
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT g.id, g.content_id, g.url, g.alt, g.caption, g.position
+		FROM content_images g
+		JOIN blog_data d ON d.id = g.content_id
+		WHERE d.status = 'published'
+		ORDER BY g.content_id, g.position ASC, g.id ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	defer rows.Close()
+
+	galleries := map[int64][]GalleryImage{}
+	for rows.Next() {
+		var g GalleryImage
+		if err := rows.Scan(&g.ID, &g.ContentID, &g.URL, &g.Alt, &g.Caption, &g.Position); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		galleries[g.ContentID] = append(galleries[g.ContentID], g)
+	}
+	return galleries, rows.Err()
+}
+
+// UpdateGalleryImage changes the alt text and caption of an image.
+func UpdateGalleryImage(contentID, imageID int64, alt, caption string) error {
+	alt = strings.TrimSpace(alt)
+	caption = strings.TrimSpace(caption)
+	if err := validateImageText(alt, caption); err != nil {
+		return err
+	}
+
+	res, err := db.DB.ExecContext(context.Background(),
+		"UPDATE content_images SET alt = ?, caption = ? WHERE id = ? AND content_id = ?",
+		alt, caption, imageID, contentID)
+	if err != nil {
+		return fmt.Errorf("failed to update image: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrImageNotFound
+	}
+	touch(contentID)
+	return nil
+}
+
+// ReorderGallery sets the display order of a gallery. imageIDs must list
+// every image of the content item exactly once.
+func ReorderGallery(contentID int64, imageIDs []int64) error {
+	current, err := GetGallery(contentID)
+	if err != nil {
+		return err
+	}
+
+	owned := make(map[int64]bool, len(current))
+	for _, g := range current {
+		owned[g.ID] = true
+	}
+	if len(imageIDs) != len(current) {
+		return fmt.Errorf("%w: image_ids must list all %d images of the gallery", ErrInvalid, len(current))
+	}
+	seen := map[int64]bool{}
+	for _, id := range imageIDs {
+		if !owned[id] || seen[id] {
+			return fmt.Errorf("%w: image %d is not in the gallery or is listed twice", ErrInvalid, id)
+		}
+		seen[id] = true
+	}
+
+	ctx := context.Background()
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, id := range imageIDs {
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE content_images SET position = ? WHERE id = ?", i+1, id); err != nil {
+			return fmt.Errorf("failed to reorder gallery: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	touch(contentID)
+	return nil
+}
+
+func DeleteGalleryImage(contentID, imageID int64) error {
+	res, err := db.DB.ExecContext(context.Background(),
+		"DELETE FROM content_images WHERE id = ? AND content_id = ?", imageID, contentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete image: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrImageNotFound
+	}
+	touch(contentID)
+	return nil
+}
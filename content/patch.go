@@ -0,0 +1,291 @@
+package content
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"example.com/portfolio/validation"
+)
+
+const (
+	StatusPublished = "published"
+	StatusDraft     = "draft"
+	StatusArchived  = "archived"
+)
+
+var (
+	Languages = []string{"en", "ru", "uz"}
+	Types     = []string{"blog", "project"}
+	// Statuses lists the publication states. Only published content is
+	// shown by the public endpoints.
+	Statuses = []string{StatusPublished, StatusDraft, StatusArchived}
+)
+
+// patchable lists the content fields a merge patch may change, by their
+// JSON names.
+var patchable = map[string]bool{
+	"language": true, "type": true, "image": true, "title": true,
+	"body": true, "meta_tag": true, "featured": true, "status": true, "project": true,
+	"translation_group": true,
+}
+
+// contentDoc is the patchable part of Content as seen by a merge patch.
+type contentDoc struct {
+	Language string   `json:"language"`
+	Type     string   `json:"type"`
+	Image    string   `json:"image"`
+	Title    string   `json:"title"`
+	Body     string   `json:"body"`
+	Tag      string   `json:"meta_tag,omitempty"`
+	Featured string   `json:"featured,omitempty"`
+	Status   string   `json:"status,omitempty"`
+	Project  *Project `json:"project,omitempty"`
+	// TranslationGroup set to null takes the item out of its group.
+	TranslationGroup int64 `json:"translation_group,omitempty"`
+}
+
+// ValidLanguage reports whether language is one of Languages.
+func ValidLanguage(language string) bool {
+	return contains(Languages, language)
+}
+
+// ValidType reports whether typ is one of Types.
+func ValidType(typ string) bool {
+	return contains(Types, typ)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate fills in the default featured flag and status and reports
+// every field of c that could not be saved, without saving it. The error
+// wraps ErrInvalid and validation.Errors.
+func (c *Content) Validate() error {
+	if c.Featured == "" {
+		c.Featured = "false"
+	}
+	if c.Status == "" {
+		c.Status = StatusPublished
+	}
+
+	var errs validation.Errors
+	c.validateFields(&errs)
+	c.validateProject(&errs)
+	return invalidFields(errs)
+}
+
+// validateFields checks the fields stored in blog_data.
+func (c *Content) validateFields(errs *validation.Errors) {
+	if errs.Required("language", c.Language) {
+		errs.OneOf("language", c.Language, Languages)
+	}
+	if errs.Required("type", c.Type) {
+		errs.OneOf("type", c.Type, Types)
+	}
+	if errs.Required("title", c.Title) {
+		errs.MaxLength("title", c.Title, 200)
+	}
+	errs.Required("body", c.Body)
+	errs.OneOf("featured", c.Featured, []string{"true", "false"})
+	errs.OneOf("status", c.Status, Statuses)
+	errs.MaxLength("meta_tag", c.Tag, 500)
+	errs.URL("image", c.Image)
+}
+
+// validateUpdate is Validate for an update of stored, the row as it is in
+// the database. Rows saved before validation was added may break its
+// rules; their fields are let through as long as they keep their stored
+// value, so that such a row can still be edited one field at a time.
+func (c *Content) validateUpdate(stored Content) error {
+	err := c.Validate()
+	var errs validation.Errors
+	if stored.ID == 0 || !errors.As(err, &errs) {
+		return err
+	}
+
+	unchanged := map[string]bool{
+		"language": c.Language == stored.Language,
+		"type":     c.Type == stored.Type,
+		"image":    c.Image == stored.Image,
+		"title":    c.Title == stored.Title,
+		"body":     c.Body == stored.Body,
+		"meta_tag": c.Tag == stored.Tag,
+		"featured": c.Featured == stored.Featured,
+		"status":   c.Status == stored.Status,
+	}
+	var changed validation.Errors
+	for _, fe := range errs {
+		if !unchanged[fe.Field] {
+			changed = append(changed, fe)
+		}
+	}
+	return invalidFields(changed)
+}
+
+// invalidFields wraps field errors in ErrInvalid, so that callers can
+// check for either.
+func invalidFields(errs validation.Errors) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrInvalid, errs)
+}
+
+// ApplyMergePatch applies an RFC 7396 JSON merge patch to the patchable
+// fields of c and validates the result. A null member removes the field,
+// which is only allowed for optional fields. The image can only be
+// replaced with an already uploaded http(s) URL. It returns the JSON
+// paths of the fields that changed, sorted; c is left untouched on error.
+func (c *Content) ApplyMergePatch(patch []byte) ([]string, error) {
+	var p interface{}
+	dec := json.NewDecoder(bytes.NewReader(patch))
+	dec.UseNumber()
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("%w: patch is not valid JSON", ErrInvalid)
+	}
+	members, ok := p.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: patch must be a JSON object", ErrInvalid)
+	}
+	var errs validation.Errors
+	for k := range members {
+		if !patchable[k] {
+			errs.Add(k, "cannot be patched")
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	if err := invalidFields(errs); err != nil {
+		return nil, err
+	}
+
+	before := contentDoc{
+		Language: c.Language, Type: c.Type, Image: c.Image, Title: c.Title,
+		Body: c.Body, Tag: c.Tag, Featured: c.Featured, Status: c.Status, Project: c.Project,
+		TranslationGroup: c.TranslationGroup,
+	}
+	// mergePatch modifies its target, so the original document is kept
+	// separately for the diff.
+	original, err := toDocument(before)
+	if err != nil {
+		return nil, err
+	}
+	target, err := toDocument(before)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := json.Marshal(mergePatch(target, members))
+	if err != nil {
+		return nil, err
+	}
+
+	var after contentDoc
+	if err := json.Unmarshal(merged, &after); err != nil {
+		if errs, ok := validation.TypeError(err); ok {
+			return nil, invalidFields(errs)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	if after.Image != before.Image {
+		var errs validation.Errors
+		if !errs.Required("image", after.Image) || !errs.URL("image", after.Image) {
+			return nil, invalidFields(errs)
+		}
+	}
+
+	next := *c
+	next.Language, next.Type, next.Image = after.Language, after.Type, after.Image
+	next.Title, next.Body, next.Tag = after.Title, after.Body, after.Tag
+	next.Featured, next.Status, next.Project = after.Featured, after.Status, after.Project
+	next.TranslationGroup = after.TranslationGroup
+	if next.Featured == "" {
+		next.Featured = "false"
+	}
+	if next.Status == "" {
+		next.Status = StatusPublished
+	}
+	if err := next.validateUpdate(*c); err != nil {
+		return nil, err
+	}
+	after.Featured, after.Status, after.Project = next.Featured, next.Status, next.Project
+
+	result, err := toDocument(after)
+	if err != nil {
+		return nil, err
+	}
+	changed := append([]string{}, diffPaths("", original, result)...)
+	sort.Strings(changed)
+
+	*c = next
+	return changed, nil
+}
+
+func toDocument(d contentDoc) (map[string]interface{}, error) {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	return m, json.Unmarshal(b, &m)
+}
+
+// mergePatch implements the MergePatch algorithm of RFC 7396.
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = map[string]interface{}{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = mergePatch(t[k], v)
+		}
+	}
+	return t
+}
+
+// diffPaths returns the dotted paths of the members that differ between
+// two JSON documents, descending into objects.
+func diffPaths(prefix string, a, b interface{}) []string {
+	am, aok := a.(map[string]interface{})
+	bm, bok := b.(map[string]interface{})
+	if !aok || !bok {
+		if reflect.DeepEqual(a, b) {
+			return nil
+		}
+		return []string{prefix}
+	}
+
+	var paths []string
+	keys := map[string]bool{}
+	for k := range am {
+		keys[k] = true
+	}
+	for k := range bm {
+		keys[k] = true
+	}
+	for k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		paths = append(paths, diffPaths(path, am[k], bm[k])...)
+	}
+	return paths
+}
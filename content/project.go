@@ -0,0 +1,170 @@
+package content
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"example.com/portfolio/db"
+	"example.com/portfolio/validation"
+)
+
+// ErrInvalid is wrapped by errors caused by invalid content input, as
+// opposed to database or upload failures.
+var ErrInvalid = errors.New("invalid content")
+
+// Project holds the structured fields of type=project content.
+type Project struct {
+	RepoURL   string   `json:"repo_url,omitempty"`
+	DemoURL   string   `json:"demo_url,omitempty"`
+	TechStack []string `json:"tech_stack,omitempty"`
+	Role      string   `json:"role,omitempty"`
+	Client    string   `json:"client,omitempty"`
+	StartDate string   `json:"start_date,omitempty"`
+	EndDate   string   `json:"end_date,omitempty"`
+}
+
+// ParseTechStack splits a comma-separated list of technologies.
+func ParseTechStack(s string) []string {
+	var stack []string
+	seen := map[string]bool{}
+	for _, t := range strings.Split(s, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[strings.ToLower(t)] {
+			continue
+		}
+		seen[strings.ToLower(t)] = true
+		stack = append(stack, t)
+	}
+	return stack
+}
+
+// Validate checks URLs, technology names and dates. Dates are YYYY-MM or
+// YYYY-MM-DD and the end date, if any, must not be before the start date.
+func (p *Project) Validate() error {
+	var errs validation.Errors
+	p.validate(&errs)
+	return invalidFields(errs)
+}
+
+func (p *Project) validate(errs *validation.Errors) {
+	errs.URL("repo_url", p.RepoURL)
+	errs.URL("demo_url", p.DemoURL)
+
+	p.TechStack = ParseTechStack(strings.Join(p.TechStack, ","))
+	if len(p.TechStack) > 30 {
+		errs.Add("tech_stack", "can have at most 30 entries")
+	}
+
+	start, err := parseProjectDate(p.StartDate)
+	if err != nil {
+		errs.Add("start_date", "must be YYYY-MM or YYYY-MM-DD")
+	}
+	end, err := parseProjectDate(p.EndDate)
+	if err != nil {
+		errs.Add("end_date", "must be YYYY-MM or YYYY-MM-DD")
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		errs.Add("end_date", "is before start_date")
+	}
+
+	errs.MaxLength("role", p.Role, 100)
+	errs.MaxLength("client", p.Client, 100)
+}
+
+func parseProjectDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01", s)
+}
+
+// validateProject rejects project fields on non-project content.
+func (c *Content) validateProject(errs *validation.Errors) {
+	if c.Project == nil {
+		return
+	}
+	if c.Type != "project" {
+		errs.Add("project", "is only allowed for type project")
+		return
+	}
+	var nested validation.Errors
+	c.Project.validate(&nested)
+	errs.Nest("project", nested)
+}
+
+// execer is *sql.DB or *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func saveProject(ctx context.Context, ex execer, id int64, p *Project) error {
+	if p == nil {
+		_, err := ex.ExecContext(ctx,
+			"DELETE FROM project_data WHERE content_id = ?", id)
+		return err
+	}
+
+	_, err := ex.ExecContext(ctx, `
+		INSERT INTO project_data (content_id, repo_url, demo_url, tech_stack, role, client, start_date, end_date)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(content_id) DO UPDATE SET
+			repo_url = excluded.repo_url,
+			demo_url = excluded.demo_url,
+			tech_stack = excluded.tech_stack,
+			role = excluded.role,
+			client = excluded.client,
+			start_date = excluded.start_date,
+			end_date = excluded.end_date;
+	`, id, p.RepoURL, p.DemoURL, strings.Join(p.TechStack, ","), p.Role, p.Client, p.StartDate, p.EndDate)
+	if err != nil {
+		return fmt.Errorf("failed to save project fields: %w", err)
+	}
+	return nil
+}
+
+// attachProjects loads the project fields of a list of contents.
+func attachProjects(contents []Content) error {
+	var ids []interface{}
+	index := map[int64]int{}
+	for i, c := range contents {
+		if c.Type == "project" {
+			ids = append(ids, c.ID)
+			index[c.ID] = i
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT content_id, repo_url, demo_url, tech_stack, role, client, start_date, end_date
+		FROM project_data
+		WHERE content_id IN (`+placeholders+`)
+	`, ids...)
+	if err != nil {
+		return fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id    int64
+			p     Project
+			stack sql.NullString
+		)
+		if err := rows.Scan(&id, &p.RepoURL, &p.DemoURL, &stack, &p.Role, &p.Client, &p.StartDate, &p.EndDate); err != nil {
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		p.TechStack = ParseTechStack(stack.String)
+		contents[index[id]].Project = &p
+	}
+	return rows.Err()
+}
@@ -0,0 +1,56 @@
+package content
+
+import (
+	"context"
+	"fmt"
+
+	"example.com/portfolio/db"
+)
+
+// Published returns every published content item in full, with its
+// project fields, series navigation and gallery, ordered by language, type
+// and newest first. Reaction counts change too often to be included.
+func Published() ([]Content, error) {
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT id, language, type, image, title, body, COALESCE(meta_tag, ''), created_at,
+			   COALESCE(updated_at, created_at), COALESCE(featured, 'false'), status, version,
+			   COALESCE(translation_group, id)
+		FROM blog_data
+		WHERE status = 'published'
+		ORDER BY language, type, created_at DESC, id DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	defer rows.Close()
+
+	contents := []Content{}
+	for rows.Next() {
+		var c Content
+		if err := rows.Scan(&c.ID, &c.Language, &c.Type, &c.Image, &c.Title, &c.Body, &c.Tag, &c.CreatedAt,
+			&c.UpdatedAt, &c.Featured, &c.Status, &c.Version, &c.TranslationGroup); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		contents = append(contents, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := attachProjects(contents); err != nil {
+		return nil, err
+	}
+	navs, err := publishedSeriesNavs()
+	if err != nil {
+		return nil, err
+	}
+	galleries, err := publishedGalleries()
+	if err != nil {
+		return nil, err
+	}
+	for i := range contents {
+		contents[i].Series = navs[contents[i].ID]
+		contents[i].Gallery = galleries[contents[i].ID]
+	}
+	return contents, nil
+}
@@ -0,0 +1,233 @@
+package content
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	maxQueryLength = 200
+	maxQueryTerms  = 16
+)
+
+// QueryError reports a search query that cannot be parsed. Its message is
+// meant to be shown to the user as is.
+type QueryError struct {
+	Msg string
+}
+
+func (e *QueryError) Error() string {
+	return e.Msg
+}
+
+func queryErrorf(format string, args ...interface{}) error {
+	return &QueryError{Msg: fmt.Sprintf(format, args...)}
+}
+
+// SearchQuery is a parsed user search query. The syntax is a list of terms
+// separated by spaces, all of which must match:
+//
+//	golang          word (the last bare word also matches as a prefix)
+//	"rest api"      exact phrase
+//	gin*            prefix
+//	-draft          exclusion, also -"exact phrase"
+//	title:golang    restrict a term to the title or body column
+//
+// Terms never reach FTS5 verbatim; they are reduced to their letters and
+// digits and quoted, so operators and punctuation cannot break the MATCH.
+type SearchQuery struct {
+	terms []queryTerm
+}
+
+type queryTerm struct {
+	field  string
+	text   string
+	phrase bool
+	prefix bool
+	negate bool
+}
+
+// ParseQuery parses s into a SearchQuery, returning a *QueryError if it is
+// malformed.
+func ParseQuery(s string) (*SearchQuery, error) {
+	if len([]rune(s)) > maxQueryLength {
+		return nil, queryErrorf("search query is too long (max %d characters)", maxQueryLength)
+	}
+
+	r := []rune(s)
+	q := &SearchQuery{}
+	lastBare := -1
+
+	for i := 0; i < len(r); {
+		if unicode.IsSpace(r[i]) {
+			i++
+			continue
+		}
+
+		var t queryTerm
+		if r[i] == '-' {
+			t.negate = true
+			i++
+			if i == len(r) || unicode.IsSpace(r[i]) {
+				return nil, queryErrorf("'-' must be followed by a word or phrase to exclude")
+			}
+		}
+
+		if colon := fieldPrefix(r[i:]); colon > 0 {
+			t.field = strings.ToLower(string(r[i : i+colon]))
+			if t.field != "title" && t.field != "body" {
+				return nil, queryErrorf("unknown field %q, use title: or body:", t.field)
+			}
+			i += colon + 1
+			if i == len(r) || unicode.IsSpace(r[i]) {
+				return nil, queryErrorf("%s: must be followed by a word or phrase", t.field)
+			}
+		}
+
+		if r[i] == '"' {
+			end := i + 1
+			for end < len(r) && r[end] != '"' {
+				end++
+			}
+			if end == len(r) {
+				return nil, queryErrorf("unterminated quote in search query")
+			}
+			t.phrase = true
+			t.text = string(r[i+1 : end])
+			i = end + 1
+			if i < len(r) && r[i] == '*' {
+				t.prefix = true
+				i++
+			}
+			if len(queryWords(t.text)) == 0 {
+				return nil, queryErrorf("empty phrase in search query")
+			}
+		} else {
+			end := i
+			for end < len(r) && !unicode.IsSpace(r[end]) && r[end] != '"' {
+				end++
+			}
+			word := string(r[i:end])
+			i = end
+			if strings.HasSuffix(word, "*") {
+				t.prefix = true
+				word = strings.TrimSuffix(word, "*")
+			}
+			if strings.Contains(word, "*") {
+				return nil, queryErrorf("'*' is only allowed at the end of a word")
+			}
+			t.text = word
+			if len(queryWords(word)) == 0 {
+				if t.negate || t.field != "" || t.prefix {
+					return nil, queryErrorf("%q contains no searchable characters", word)
+				}
+				continue
+			}
+		}
+
+		if !t.phrase && !t.prefix && !t.negate {
+			lastBare = len(q.terms)
+		}
+		q.terms = append(q.terms, t)
+		if len(q.terms) > maxQueryTerms {
+			return nil, queryErrorf("search query has too many terms (max %d)", maxQueryTerms)
+		}
+	}
+
+	positive := false
+	for _, t := range q.terms {
+		if !t.negate {
+			positive = true
+		}
+	}
+	if len(q.terms) == 0 {
+		return nil, queryErrorf("search query contains no searchable words")
+	}
+	if !positive {
+		return nil, queryErrorf("search query needs at least one term that is not excluded")
+	}
+
+	// Keep the search-as-you-type behaviour: a trailing bare word is
+	// treated as a prefix.
+	if lastBare >= 0 && lastBare == len(q.terms)-1 {
+		q.terms[lastBare].prefix = true
+	}
+
+	return q, nil
+}
+
+// fieldPrefix returns the length of a leading "name" in "name:term", or 0.
+func fieldPrefix(r []rune) int {
+	for i, c := range r {
+		if c == ':' {
+			return i
+		}
+		if !unicode.IsLetter(c) {
+			return 0
+		}
+	}
+	return 0
+}
+
+func queryWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// fts compiles the query into an FTS5 MATCH expression. If stem is not
+// nil every term is reduced to stems and matched as a prefix, which is how
+// blog_search_stem is queried. Stemming can drop every word of a term, e.g.
+// the Uzbek hard and soft signs, so a *QueryError is returned if no term
+// that must match is left.
+func (q *SearchQuery) fts(stem func(string) []string) (string, error) {
+	var pos, neg []string
+	for _, t := range q.terms {
+		words := queryWords(t.text)
+		prefix := t.prefix
+		if stem != nil {
+			words = stem(t.text)
+			prefix = true
+		}
+		if len(words) == 0 {
+			continue
+		}
+
+		expr := `"` + strings.Join(words, " ") + `"`
+		if prefix {
+			expr += "*"
+		}
+		if t.field != "" {
+			expr = t.field + " : " + expr
+		}
+
+		if t.negate {
+			neg = append(neg, expr)
+		} else {
+			pos = append(pos, expr)
+		}
+	}
+
+	if len(pos) == 0 {
+		return "", queryErrorf("search query contains no searchable words")
+	}
+
+	match := "(" + strings.Join(pos, " AND ") + ")"
+	if len(neg) > 0 {
+		match += " NOT (" + strings.Join(neg, " OR ") + ")"
+	}
+	return match, nil
+}
+
+// stems returns the stems of the words that must match, for highlighting
+// results of a stemmed search.
+func (q *SearchQuery) stems(stem func(string) []string) []string {
+	var out []string
+	for _, t := range q.terms {
+		if !t.negate {
+			out = append(out, stem(t.text)...)
+		}
+	}
+	return out
+}
@@ -0,0 +1,30 @@
+package content
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFTSStemmedToNothing(t *testing.T) {
+	stem := func(s string) []string { return stemTokens("uz", s) }
+
+	for _, s := range []string{"ъ", "ь", "title:ъ", "ъ ь", "ъ -dastur"} {
+		q, err := ParseQuery(s)
+		if err != nil {
+			t.Fatalf("ParseQuery(%q): %v", s, err)
+		}
+		match, err := q.fts(stem)
+		var qe *QueryError
+		if !errors.As(err, &qe) {
+			t.Errorf("fts(%q) = %q, %v; want a *QueryError", s, match, err)
+		}
+	}
+
+	q, err := ParseQuery("ъ dastur")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match, err := q.fts(stem); err != nil || match == "()" {
+		t.Errorf(`fts("ъ dastur") = %q, %v; want a match on dastur`, match, err)
+	}
+}
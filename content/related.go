@@ -0,0 +1,251 @@
+package content
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"example.com/portfolio/db"
+)
+
+const (
+	relatedTermLimit      = 12
+	relatedCandidateLimit = 50
+	relatedTextWeight     = 0.6
+	relatedTagWeight      = 0.4
+)
+
+var stopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true,
+	"you": true, "all": true, "any": true, "can": true, "had": true, "her": true,
+	"was": true, "one": true, "our": true, "out": true, "has": true, "have": true,
+	"this": true, "that": true, "with": true, "from": true, "they": true, "will": true,
+	"what": true, "when": true, "your": true, "which": true, "their": true, "there": true,
+	"about": true, "into": true, "than": true, "then": true, "them": true, "these": true,
+	"some": true, "would": true, "other": true, "how": true, "its": true, "also": true,
+	"это": true, "как": true, "для": true, "что": true, "или": true, "так": true,
+	"bir": true, "uchun": true, "bilan": true, "ham": true, "va": true, "bu": true,
+}
+
+// GetRelated returns up to limit items listed in the language of the
+// content with the given id, which falls back along LanguageChain as
+// listings do, ranked by bm25 similarity of its most frequent title/body
+// terms combined with the overlap of meta tags. Its own translations are
+// left out.
+func GetRelated(id int64, limit int) ([]Content, error) {
+	src, err := GetById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	srcTags := splitTags(src.Tag)
+	chain := fallbackChain(src.Language)
+	candidates := map[int64]*relatedCandidate{}
+
+	if match := relatedMatch(src.Title, src.Body); match != "" {
+		query := `
+			SELECT d.id, d.language, d.type, d.image, d.title, d.body,
+				   d.meta_tag, d.created_at, d.featured, bm25(blog_search) AS score
+			FROM blog_search
+			JOIN blog_data d ON d.id = blog_search.rowid
+			WHERE blog_search MATCH ?
+			  AND d.id IN (` + preferredIDs("COALESCE(translation_group, id) != ?") + `)
+			ORDER BY score ASC
+			LIMIT ?;
+		`
+		rows, err := db.DB.QueryContext(context.Background(), query,
+			match, chain, chain, src.TranslationGroup, relatedCandidateLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute SQL: %w", err)
+		}
+		for rows.Next() {
+			var (
+				c    Content
+				bm25 float64
+			)
+			if err := rows.Scan(&c.ID, &c.Language, &c.Type, &c.Image, &c.Title,
+				&c.Body, &c.Tag, &c.CreatedAt, &c.Featured, &bm25); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan row: %w", err)
+			}
+			// bm25() is negative, more negative meaning a better match.
+			candidates[c.ID] = &relatedCandidate{content: c, text: -bm25}
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+	}
+
+	if len(srcTags) > 0 {
+		conds := make([]string, 0, len(srcTags))
+		args := []interface{}{chain, chain, src.TranslationGroup}
+		for _, t := range srcTags {
+			conds = append(conds, "instr(lower(meta_tag), ?) > 0")
+			args = append(args, t)
+		}
+		args = append(args, relatedCandidateLimit)
+
+		query := `
+			SELECT id, language, type, image, title, body, meta_tag, created_at, featured
+			FROM blog_data
+			WHERE id IN (` + preferredIDs("COALESCE(translation_group, id) != ?") + `)
+			  AND (` + strings.Join(conds, " OR ") + `)
+			ORDER BY created_at DESC
+			LIMIT ?;
+		`
+		rows, err := db.DB.QueryContext(context.Background(), query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute SQL: %w", err)
+		}
+		for rows.Next() {
+			var c Content
+			if err := rows.Scan(&c.ID, &c.Language, &c.Type, &c.Image, &c.Title,
+				&c.Body, &c.Tag, &c.CreatedAt, &c.Featured); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan row: %w", err)
+			}
+			if _, ok := candidates[c.ID]; !ok {
+				candidates[c.ID] = &relatedCandidate{content: c}
+			}
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+	}
+
+	var maxText float64
+	for _, rc := range candidates {
+		if rc.text > maxText {
+			maxText = rc.text
+		}
+	}
+
+	ranked := make([]*relatedCandidate, 0, len(candidates))
+	for _, rc := range candidates {
+		var text, tag float64
+		if maxText > 0 {
+			text = rc.text / maxText
+		}
+		if len(srcTags) > 0 {
+			tag = float64(tagOverlap(srcTags, splitTags(rc.content.Tag))) / float64(len(srcTags))
+		}
+		rc.score = relatedTextWeight*text + relatedTagWeight*tag
+		if rc.score > 0 {
+			ranked = append(ranked, rc)
+		}
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].content.CreatedAt > ranked[j].content.CreatedAt
+	})
+
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	contents := make([]Content, 0, len(ranked))
+	for _, rc := range ranked {
+		score := rc.score
+		rc.content.Score = &score
+		if rc.content.Language != src.Language {
+			rc.content.RequestedLanguage = src.Language
+		}
+		contents = append(contents, rc.content)
+	}
+
+	if err := decorate(contents); err != nil {
+		return nil, err
+	}
+
+	return contents, nil
+}
+
+type relatedCandidate struct {
+	content Content
+	text    float64
+	score   float64
+}
+
+// relatedMatch builds an OR query over the most frequent terms of the
+// source item, counting title terms more heavily than body terms.
+func relatedMatch(title, body string) string {
+	freq := map[string]int{}
+	for _, t := range terms(title) {
+		freq[t] += 3
+	}
+	for _, t := range terms(body) {
+		freq[t]++
+	}
+
+	words := make([]string, 0, len(freq))
+	for w := range freq {
+		words = append(words, w)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if freq[words[i]] != freq[words[j]] {
+			return freq[words[i]] > freq[words[j]]
+		}
+		return words[i] < words[j]
+	})
+	if len(words) > relatedTermLimit {
+		words = words[:relatedTermLimit]
+	}
+
+	quoted := make([]string, 0, len(words))
+	for _, w := range words {
+		quoted = append(quoted, `"`+strings.ReplaceAll(w, `"`, `""`)+`"`)
+	}
+	return strings.Join(quoted, " OR ")
+}
+
+func terms(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	out := fields[:0]
+	for _, f := range fields {
+		if len([]rune(f)) < 3 || stopwords[f] {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+func splitTags(s string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, t := range strings.Split(s, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		tags = append(tags, t)
+	}
+	return tags
+}
+
+func tagOverlap(a, b []string) int {
+	set := make(map[string]bool, len(b))
+	for _, t := range b {
+		set[t] = true
+	}
+	n := 0
+	for _, t := range a {
+		if set[t] {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,135 @@
+package content
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"example.com/portfolio/db"
+	"example.com/portfolio/reactions"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// Modified returns UpdatedAt as a time, or the zero time if it is unknown.
+func (c Content) Modified() time.Time {
+	t, err := time.ParseInLocation(timeLayout, c.UpdatedAt, time.UTC)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// Revision loads only what identifies the stored revision of a content
+// item: its ID, version, update time and status, and what State needs.
+// It is much cheaper than GetById and is used to answer conditional
+// requests. UpdatedAt also covers the last change to the reactions.
+func Revision(id int64) (Content, error) {
+	c := Content{ID: id}
+	err := db.DB.QueryRowContext(context.Background(),
+		"SELECT version, attached_version, COALESCE(updated_at, created_at), status FROM blog_data WHERE id = ?", id).
+		Scan(&c.Version, &c.attached, &c.UpdatedAt, &c.Status)
+	if err == sql.ErrNoRows {
+		return c, fmt.Errorf("blog not found")
+	}
+	if err != nil {
+		return c, fmt.Errorf("failed to get content: %w", err)
+	}
+
+	var reacted string
+	c.reactionChanges, reacted, err = reactions.Changes(id)
+	if err != nil {
+		return c, fmt.Errorf("failed to get reaction changes: %w", err)
+	}
+	if reacted > c.UpdatedAt {
+		c.UpdatedAt = reacted
+	}
+	return c, nil
+}
+
+// State is the ETag of everything served with a content item loaded by
+// Revision: the item itself, its gallery and series navigation, and its
+// reaction counts. It extends ETag, and If-Match only compares that part,
+// so that reactions or gallery edits do not fail an admin's update.
+func (c Content) State() string {
+	return fmt.Sprintf(`"%d-%d.%d.%d"`, c.ID, c.Version, c.attached, c.reactionChanges)
+}
+
+// SameRevision reports whether the entity tag etag, as returned by ETag or
+// State, names the revision of c.
+func (c Content) SameRevision(etag string) bool {
+	rev := c.ETag()
+	return etag == rev || strings.HasPrefix(etag, strings.TrimSuffix(rev, `"`)+".")
+}
+
+// contentState summarizes blog_data as a whole: the number of rows, the
+// sum of their versions and the highest ID, and the latest update time.
+type contentState struct {
+	count, versions, maxID int64
+	updated                string
+}
+
+func loadContentState() (contentState, error) {
+	var st contentState
+	err := db.DB.QueryRowContext(context.Background(), `
+		SELECT COUNT(*), COALESCE(SUM(version + attached_version), 0), COALESCE(MAX(id), 0),
+			   COALESCE(MAX(COALESCE(updated_at, created_at)), '')
+		FROM blog_data
+	`).Scan(&st.count, &st.versions, &st.maxID, &st.updated)
+	if err != nil {
+		return contentState{}, fmt.Errorf("failed to get content state: %w", err)
+	}
+	return st, nil
+}
+
+// ListState summarizes blog_data and the reactions as a whole, so that a
+// weak ETag and a Last-Modified time for any listing can be derived
+// without running the listing itself. Any insert, update, touch, delete
+// or reaction changes the ETag. The blog_data part is read from the cache
+// and only recomputed after a write has invalidated it.
+// key distinguishes the listings, e.g. the request's query string.
+func ListState(key string) (string, time.Time, error) {
+	st, err := stateCache.GetOrLoad(struct{}{}, loadContentState)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	updated := st.updated
+	reactionChanges, reacted, err := reactions.AllChanges()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to get reaction changes: %w", err)
+	}
+	if reacted > updated {
+		updated = reacted
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	etag := fmt.Sprintf(`W/"%d-%d-%d-%d-%x"`, st.count, st.versions, st.maxID, reactionChanges, h.Sum32())
+
+	modified, _ := time.ParseInLocation(timeLayout, updated, time.UTC)
+	return etag, modified, nil
+}
+
+// touch marks content items as changed when data attached to them, such as
+// their gallery or series, changes, so that their State changes too. Their
+// version, and so the ETag checked by If-Match, stays the same.
+func touch(ids ...int64) {
+	if len(ids) == 0 {
+		return
+	}
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	_, _ = db.DB.ExecContext(context.Background(), `
+		UPDATE blog_data
+		SET attached_version = attached_version + 1, updated_at = datetime('now')
+		WHERE id IN (`+placeholders+`)
+	`, args...)
+	invalidate()
+}
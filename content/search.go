@@ -0,0 +1,175 @@
+package content
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"example.com/portfolio/db"
+)
+
+// indexStemmed keeps blog_search_stem in step with blog_data. Rows in
+// languages without a Go stemmer are only indexed by blog_search.
+func indexStemmed(id int64, language, title, body string) error {
+	if _, err := db.DB.ExecContext(context.Background(),
+		"DELETE FROM blog_search_stem WHERE rowid = ?", id); err != nil {
+		return err
+	}
+	if !isStemmed(language) {
+		return nil
+	}
+	_, err := db.DB.ExecContext(context.Background(),
+		"INSERT INTO blog_search_stem(rowid, title, body) VALUES (?, ?, ?)",
+		id, stemText(language, title), stemText(language, body))
+	return err
+}
+
+// reindexStemmed runs indexStemmed for content that has already been
+// saved, so a failure is logged instead of failing the write. Rows left
+// out of the index are added by SyncSearchIndex at the next start.
+func reindexStemmed(c *Content) {
+	if err := indexStemmed(c.ID, c.Language, c.Title, c.Body); err != nil {
+		log.Printf("⚠️ Could not index content %d in blog_search_stem: %v", c.ID, err)
+	}
+}
+
+// SyncSearchIndex stems and indexes any ru/uz content that is missing from
+// blog_search_stem, e.g. rows written before the index existed.
+func SyncSearchIndex() error {
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT id, language, title, body
+		FROM blog_data
+		WHERE language IN ('ru', 'uz')
+		  AND id NOT IN (SELECT rowid FROM blog_search_stem);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to execute SQL: %w", err)
+	}
+
+	var pending []Content
+	for rows.Next() {
+		var c Content
+		if err := rows.Scan(&c.ID, &c.Language, &c.Title, &c.Body); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		pending = append(pending, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, c := range pending {
+		if err := indexStemmed(c.ID, c.Language, c.Title, c.Body); err != nil {
+			return fmt.Errorf("failed to index content %d: %w", c.ID, err)
+		}
+	}
+	return nil
+}
+
+type wordSpan struct {
+	start, end int
+	match      bool
+}
+
+// stemmedHighlight emulates the FTS5 highlight() and snippet() functions for
+// blog_search_stem, whose stored text is stemmed and therefore unusable for
+// display. Words of the original text whose stem starts with one of the
+// query stems are wrapped in the highlight markers.
+func stemmedHighlight(language, text string, stems []string, hl Highlight, snippet bool) string {
+	isWord := func(r rune) bool {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return true
+		}
+		return language == "uz" && strings.ContainsRune("'ʻʼ‘’`´", r)
+	}
+
+	var words []wordSpan
+	start := -1
+	for i, r := range text {
+		if isWord(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			words = append(words, wordSpan{start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, wordSpan{start: start, end: len(text)})
+	}
+
+	for i := range words {
+		for _, ws := range stemTokens(language, text[words[i].start:words[i].end]) {
+			for _, qs := range stems {
+				if qs != "" && strings.HasPrefix(ws, qs) {
+					words[i].match = true
+				}
+			}
+		}
+	}
+
+	if len(words) == 0 {
+		if snippet {
+			return ""
+		}
+		return text
+	}
+
+	from, to := 0, len(words)
+	if snippet && len(words) > hl.Tokens {
+		// Pick the window with the most matching words.
+		best, count := 0, 0
+		for i := 0; i < hl.Tokens; i++ {
+			if words[i].match {
+				count++
+			}
+		}
+		bestCount := count
+		for i := hl.Tokens; i < len(words); i++ {
+			if words[i].match {
+				count++
+			}
+			if words[i-hl.Tokens].match {
+				count--
+			}
+			if count > bestCount {
+				best, bestCount = i-hl.Tokens+1, count
+			}
+		}
+		from, to = best, best+hl.Tokens
+	}
+
+	var b strings.Builder
+	pos := 0
+	if snippet {
+		pos = words[from].start
+		if from > 0 {
+			b.WriteString(hl.Ellipsis)
+		}
+	}
+	for _, w := range words[from:to] {
+		b.WriteString(text[pos:w.start])
+		if w.match {
+			b.WriteString(hl.Open)
+			b.WriteString(text[w.start:w.end])
+			b.WriteString(hl.Close)
+		} else {
+			b.WriteString(text[w.start:w.end])
+		}
+		pos = w.end
+	}
+	if !snippet {
+		b.WriteString(text[pos:])
+	} else if to < len(words) {
+		b.WriteString(hl.Ellipsis)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,400 @@
+package content
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"example.com/portfolio/db"
+	"example.com/portfolio/validation"
+)
+
+var ErrSeriesNotFound = errors.New("series not found")
+
+// Series groups content items into an ordered, multi-part sequence. A
+// content item belongs to at most one series.
+type Series struct {
+	ID          int64        `json:"id"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	CreatedAt   string       `json:"created_at"`
+	Items       []SeriesLink `json:"items,omitempty"`
+}
+
+type SeriesLink struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	Part  int    `json:"part"`
+	URL   string `json:"url"`
+}
+
+// SeriesNav is attached to a content item that is part of a series.
+type SeriesNav struct {
+	ID    int64       `json:"id"`
+	Title string      `json:"title"`
+	Part  int         `json:"part"`
+	Total int         `json:"total"`
+	Prev  *SeriesLink `json:"prev,omitempty"`
+	Next  *SeriesLink `json:"next,omitempty"`
+}
+
+func (s *Series) validate() error {
+	s.Title = strings.TrimSpace(s.Title)
+	var errs validation.Errors
+	if errs.Required("title", s.Title) {
+		errs.MaxLength("title", s.Title, 200)
+	}
+	errs.MaxLength("description", s.Description, 2000)
+	return invalidFields(errs)
+}
+
+func (s *Series) Add() error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+
+	res, err := db.DB.ExecContext(context.Background(),
+		"INSERT INTO series (title, description) VALUES (?, ?)", s.Title, s.Description)
+	if err != nil {
+		return fmt.Errorf("failed to insert series: %w", err)
+	}
+	s.ID, _ = res.LastInsertId()
+
+	return db.DB.QueryRowContext(context.Background(),
+		"SELECT created_at FROM series WHERE id = ?", s.ID).Scan(&s.CreatedAt)
+}
+
+func (s *Series) Update() error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+
+	res, err := db.DB.ExecContext(context.Background(),
+		"UPDATE series SET title = ?, description = ? WHERE id = ?", s.Title, s.Description, s.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update series: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrSeriesNotFound
+	}
+	touch(SeriesMembers(s.ID)...)
+	return nil
+}
+
+func DeleteSeries(id int64) error {
+	members := SeriesMembers(id)
+	ctx := context.Background()
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM series WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete series: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrSeriesNotFound
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM series_items WHERE series_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete series items: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	touch(members...)
+	return nil
+}
+
+func ListSeries() ([]Series, error) {
+	rows, err := db.DB.QueryContext(context.Background(),
+		"SELECT id, title, description, created_at FROM series ORDER BY created_at DESC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	defer rows.Close()
+
+	list := []Series{}
+	for rows.Next() {
+		var s Series
+		if err := rows.Scan(&s.ID, &s.Title, &s.Description, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		list = append(list, s)
+	}
+	return list, rows.Err()
+}
+
+// GetSeries returns a series with its published items in order.
+func GetSeries(id int64) (Series, error) {
+	var s Series
+	err := db.DB.QueryRowContext(context.Background(),
+		"SELECT id, title, description, created_at FROM series WHERE id = ?", id).
+		Scan(&s.ID, &s.Title, &s.Description, &s.CreatedAt)
+	if err == sql.ErrNoRows {
+		return s, ErrSeriesNotFound
+	}
+	if err != nil {
+		return s, fmt.Errorf("failed to get series: %w", err)
+	}
+
+	s.Items, err = seriesItems(id, true)
+	return s, err
+}
+
+// seriesItems returns the items of a series in order. Readers only see
+// published items, numbered among themselves; changes to the series work
+// on all of them.
+func seriesItems(seriesID int64, publishedOnly bool) ([]SeriesLink, error) {
+	filter := ""
+	if publishedOnly {
+		filter = "AND d.status = 'published'"
+	}
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT d.id, d.title
+		FROM series_items i
+		JOIN blog_data d ON d.id = i.content_id
+		WHERE i.series_id = ? `+filter+`
+		ORDER BY i.position ASC
+	`, seriesID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	defer rows.Close()
+
+	items := []SeriesLink{}
+	for rows.Next() {
+		var l SeriesLink
+		if err := rows.Scan(&l.ID, &l.Title); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		l.Part = len(items) + 1
+		l.URL = fmt.Sprintf("/blog/%d", l.ID)
+		items = append(items, l)
+	}
+	return items, rows.Err()
+}
+
+// SetSeriesItems replaces the members of a series with contentIDs, in that
+// order. Items that already belong to another series are rejected.
+func SetSeriesItems(seriesID int64, contentIDs []int64) error {
+	previous := SeriesMembers(seriesID)
+	ctx := context.Background()
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM series WHERE id = ?", seriesID).Scan(&exists); err != nil {
+		return err
+	}
+	if exists == 0 {
+		return ErrSeriesNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM series_items WHERE series_id = ?", seriesID); err != nil {
+		return fmt.Errorf("failed to clear series items: %w", err)
+	}
+
+	seen := map[int64]bool{}
+	for i, id := range contentIDs {
+		if seen[id] {
+			return fmt.Errorf("%w: content %d is listed twice", ErrInvalid, id)
+		}
+		seen[id] = true
+
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM blog_data WHERE id = ?", id).Scan(&exists); err != nil {
+			return err
+		}
+		if exists == 0 {
+			return fmt.Errorf("%w: content %d does not exist", ErrInvalid, id)
+		}
+
+		var other int64
+		err := tx.QueryRowContext(ctx, "SELECT series_id FROM series_items WHERE content_id = ?", id).Scan(&other)
+		if err == nil {
+			return fmt.Errorf("%w: content %d already belongs to series %d", ErrInvalid, id, other)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO series_items (series_id, content_id, position) VALUES (?, ?, ?)",
+			seriesID, id, i+1); err != nil {
+			return fmt.Errorf("failed to insert series item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	touch(append(previous, contentIDs...)...)
+	return nil
+}
+
+// AddSeriesItem appends a content item to the end of a series.
+func AddSeriesItem(seriesID, contentID int64) error {
+	items, err := seriesItems(seriesID, false)
+	if err != nil {
+		return err
+	}
+	ids := make([]int64, 0, len(items)+1)
+	for _, it := range items {
+		ids = append(ids, it.ID)
+	}
+	return SetSeriesItems(seriesID, append(ids, contentID))
+}
+
+func RemoveSeriesItem(seriesID, contentID int64) error {
+	res, err := db.DB.ExecContext(context.Background(),
+		"DELETE FROM series_items WHERE series_id = ? AND content_id = ?", seriesID, contentID)
+	if err != nil {
+		return fmt.Errorf("failed to remove series item: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: content %d is not part of series %d", ErrInvalid, contentID, seriesID)
+	}
+	touch(append(SeriesMembers(seriesID), contentID)...)
+	return nil
+}
+
+// SeriesMembers returns the IDs of the content items in a series. Their
+// navigation changes whenever the series does.
+func SeriesMembers(seriesID int64) []int64 {
+	items, _ := seriesItems(seriesID, false)
+	ids := make([]int64, len(items))
+	for i, it := range items {
+		ids[i] = it.ID
+	}
+	return ids
+}
+
+// seriesSiblings returns the other items of the series id is part of,
+// whose navigation changes with id.
+func seriesSiblings(id int64) []int64 {
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT content_id FROM series_items
+		WHERE series_id = (SELECT series_id FROM series_items WHERE content_id = ?)
+		  AND content_id != ?
+	`, id, id)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var sid int64
+		if rows.Scan(&sid) == nil {
+			ids = append(ids, sid)
+		}
+	}
+	return ids
+}
+
+// seriesNav returns the series navigation for a content item, or nil if it
+// is not part of a series.
+func seriesNav(contentID int64) (*SeriesNav, error) {
+	var nav SeriesNav
+	err := db.DB.QueryRowContext(context.Background(), `
+		SELECT s.id, s.title
+		FROM series_items i
+		JOIN series s ON s.id = i.series_id
+		WHERE i.content_id = ?
+	`, contentID).Scan(&nav.ID, &nav.Title)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series: %w", err)
+	}
+
+	items, err := seriesItems(nav.ID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	nav.Total = len(items)
+	for i, it := range items {
+		if it.ID != contentID {
+			continue
+		}
+		nav.Part = it.Part
+		if i > 0 {
+			prev := items[i-1]
+			nav.Prev = &prev
+		}
+		if i < len(items)-1 {
+			next := items[i+1]
+			nav.Next = &next
+		}
+	}
+	return &nav, nil
+}
+
+// publishedSeriesNavs is seriesNav for every published item in a series,
+// by content ID, loaded with a single query.
+func publishedSeriesNavs() (map[int64]*SeriesNav, error) {
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT s.id, s.title, d.id, d.title
+		FROM series_items i
+		JOIN series s ON s.id = i.series_id
+		JOIN blog_data d ON d.id = i.content_id
+		WHERE d.status = 'published'
+		ORDER BY s.id, i.position ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		order  []int64
+		titles = map[int64]string{}
+		items  = map[int64][]SeriesLink{}
+	)
+	for rows.Next() {
+		var (
+			seriesID int64
+			title    string
+			l        SeriesLink
+		)
+		if err := rows.Scan(&seriesID, &title, &l.ID, &l.Title); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if _, ok := titles[seriesID]; !ok {
+			order = append(order, seriesID)
+			titles[seriesID] = title
+		}
+		l.Part = len(items[seriesID]) + 1
+		l.URL = fmt.Sprintf("/blog/%d", l.ID)
+		items[seriesID] = append(items[seriesID], l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	navs := map[int64]*SeriesNav{}
+	for _, seriesID := range order {
+		list := items[seriesID]
+		for i, it := range list {
+			nav := &SeriesNav{ID: seriesID, Title: titles[seriesID], Part: it.Part, Total: len(list)}
+			if i > 0 {
+				prev := list[i-1]
+				nav.Prev = &prev
+			}
+			if i < len(list)-1 {
+				next := list[i+1]
+				nav.Next = &next
+			}
+			navs[it.ID] = nav
+		}
+	}
+	return navs, nil
+}
@@ -0,0 +1,251 @@
+package content
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stemmedLanguages are indexed into blog_search_stem with a Go-side
+// stemmer, because the porter tokenizer of blog_search only knows English.
+var stemmedLanguages = map[string]func(string) string{
+	"ru": stemRussian,
+	"uz": stemUzbek,
+}
+
+func isStemmed(language string) bool {
+	_, ok := stemmedLanguages[language]
+	return ok
+}
+
+// stemTokens splits text into words and reduces each one to its stem using
+// the stemmer of the given language.
+func stemTokens(language, text string) []string {
+	stem, ok := stemmedLanguages[language]
+	if !ok {
+		stem = func(w string) string { return w }
+	}
+
+	text = strings.ToLower(text)
+	if language == "uz" {
+		text = uzbekToLatin(text)
+	}
+
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = stem(w)
+	}
+	return words
+}
+
+func stemText(language, text string) string {
+	return strings.Join(stemTokens(language, text), " ")
+}
+
+// Russian, following the Snowball stemming algorithm.
+
+var (
+	ruVowels = "аеиоуыэюя"
+
+	ruPerfectiveGerund1 = []string{"в", "вши", "вшись"}
+	ruPerfectiveGerund2 = []string{"ив", "ивши", "ившись", "ыв", "ывши", "ывшись"}
+	ruAdjective         = []string{"ее", "ие", "ые", "ое", "ими", "ыми", "ей", "ий", "ый", "ой", "ем", "им", "ым", "ом",
+		"его", "ого", "ему", "ому", "их", "ых", "ую", "юю", "ая", "яя", "ою", "ею"}
+	ruParticiple1 = []string{"ем", "нн", "вш", "ющ", "щ"}
+	ruParticiple2 = []string{"ивш", "ывш", "ующ"}
+	ruReflexive   = []string{"ся", "сь"}
+	ruVerb1       = []string{"ла", "на", "ете", "йте", "ли", "й", "л", "ем", "н", "ло", "но", "ет", "ют", "ны", "ть", "ешь", "нно"}
+	ruVerb2       = []string{"ила", "ыла", "ена", "ейте", "уйте", "ите", "или", "ыли", "ей", "уй", "ил", "ыл", "им", "ым", "ен",
+		"ило", "ыло", "ено", "ят", "ует", "уют", "ит", "ыт", "ены", "ить", "ыть", "ишь", "ую", "ю"}
+	ruNoun = []string{"а", "ев", "ов", "ие", "ье", "е", "иями", "ями", "ами", "еи", "ии", "и", "ией", "ей", "ой", "ий", "й",
+		"иям", "ям", "ием", "ем", "ам", "ом", "о", "у", "ах", "иях", "ях", "ы", "ь", "ию", "ью", "ю", "ия", "ья", "я"}
+	ruSuperlative  = []string{"ейш", "ейше"}
+	ruDerivational = []string{"ост", "ость"}
+)
+
+func isRuVowel(r rune) bool {
+	return strings.ContainsRune(ruVowels, r)
+}
+
+func stemRussian(word string) string {
+	w := []rune(strings.ReplaceAll(word, "ё", "е"))
+	for _, r := range w {
+		if r < 'а' || r > 'я' {
+			return word
+		}
+	}
+
+	rv := len(w)
+	for i, r := range w {
+		if isRuVowel(r) {
+			rv = i + 1
+			break
+		}
+	}
+	r1 := ruRegion(w, 0)
+	r2 := ruRegion(w, r1)
+
+	// Step 1.
+	if n := ruSuffixAfter(w, rv, ruPerfectiveGerund1, "ая"); n > 0 {
+		w = w[:len(w)-n]
+	} else if n := ruSuffix(w, rv, ruPerfectiveGerund2); n > 0 {
+		w = w[:len(w)-n]
+	} else {
+		if n := ruSuffix(w, rv, ruReflexive); n > 0 {
+			w = w[:len(w)-n]
+		}
+		if n := ruAdjectival(w, rv); n > 0 {
+			w = w[:len(w)-n]
+		} else if n := ruVerb(w, rv); n > 0 {
+			w = w[:len(w)-n]
+		} else if n := ruSuffix(w, rv, ruNoun); n > 0 {
+			w = w[:len(w)-n]
+		}
+	}
+
+	// Step 2.
+	if len(w) > rv && w[len(w)-1] == 'и' {
+		w = w[:len(w)-1]
+	}
+
+	// Step 3.
+	if n := ruSuffix(w, r2, ruDerivational); n > 0 {
+		w = w[:len(w)-n]
+	}
+
+	// Step 4.
+	if n := ruSuffix(w, rv, ruSuperlative); n > 0 {
+		w = w[:len(w)-n]
+	}
+	if len(w)-2 >= rv && strings.HasSuffix(string(w), "нн") {
+		w = w[:len(w)-1]
+	} else if len(w) > rv && w[len(w)-1] == 'ь' {
+		w = w[:len(w)-1]
+	}
+
+	return string(w)
+}
+
+// ruRegion returns the start of the region after the first non-vowel that
+// follows a vowel, searching from start.
+func ruRegion(w []rune, start int) int {
+	for i := start + 1; i < len(w); i++ {
+		if !isRuVowel(w[i]) && isRuVowel(w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+// ruSuffix returns the rune length of the longest suffix from the list that
+// lies entirely inside the region starting at region, or 0.
+func ruSuffix(w []rune, region int, suffixes []string) int {
+	best := 0
+	s := string(w)
+	for _, suf := range suffixes {
+		n := len([]rune(suf))
+		if n > best && len(w)-n >= region && strings.HasSuffix(s, suf) {
+			best = n
+		}
+	}
+	return best
+}
+
+// ruSuffixAfter is ruSuffix for endings that must be preceded by one of the
+// runes in prev, which itself has to be inside the region.
+func ruSuffixAfter(w []rune, region int, suffixes []string, prev string) int {
+	best := 0
+	s := string(w)
+	for _, suf := range suffixes {
+		n := len([]rune(suf))
+		i := len(w) - n
+		if n > best && i-1 >= region && strings.HasSuffix(s, suf) && strings.ContainsRune(prev, w[i-1]) {
+			best = n
+		}
+	}
+	return best
+}
+
+func ruAdjectival(w []rune, rv int) int {
+	n := ruSuffix(w, rv, ruAdjective)
+	if n == 0 {
+		return 0
+	}
+	rest := w[:len(w)-n]
+	if p := ruSuffixAfter(rest, rv, ruParticiple1, "ая"); p > 0 {
+		return n + p
+	}
+	if p := ruSuffix(rest, rv, ruParticiple2); p > 0 {
+		return n + p
+	}
+	return n
+}
+
+func ruVerb(w []rune, rv int) int {
+	n1 := ruSuffixAfter(w, rv, ruVerb1, "ая")
+	n2 := ruSuffix(w, rv, ruVerb2)
+	if n2 > n1 {
+		return n2
+	}
+	return n1
+}
+
+// Uzbek is agglutinative, so stemming strips the common predicate, case,
+// possessive and plural suffixes from the right, one layer at a time.
+
+const uzMinStem = 3
+
+var (
+	uzPredicate  = []string{"man", "san", "miz", "siz", "dir"}
+	uzCase       = []string{"ning", "dagi", "gacha", "dan", "tan", "ni", "ga", "ka", "qa", "da", "ta"}
+	uzPossessive = []string{"imiz", "ingiz", "lari", "miz", "ngiz", "im", "ing", "si", "i", "m"}
+	uzPlural     = []string{"lar"}
+)
+
+var uzCyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "j",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "x", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'ъ': "", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'ў': "o", 'қ': "q", 'ғ': "g", 'ҳ': "h",
+}
+
+// uzbekToLatin maps Cyrillic Uzbek to the Latin alphabet and folds the
+// apostrophe letters (oʻ, gʻ) and the tutuq belgisi onto plain letters, so
+// both scripts and all apostrophe variants index to the same tokens.
+func uzbekToLatin(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if l, ok := uzCyrillic[r]; ok {
+			b.WriteString(l)
+			continue
+		}
+		switch r {
+		case '\'', 'ʻ', 'ʼ', '‘', '’', '`', '´':
+			// dropped
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func stemUzbek(word string) string {
+	for _, group := range [][]string{uzPredicate, uzCase, uzPossessive, uzPlural} {
+		word = uzStrip(word, group)
+	}
+	return word
+}
+
+func uzStrip(word string, suffixes []string) string {
+	best := ""
+	for _, suf := range suffixes {
+		if len(suf) > len(best) && strings.HasSuffix(word, suf) &&
+			len([]rune(word))-len([]rune(suf)) >= uzMinStem {
+			best = suf
+		}
+	}
+	return strings.TrimSuffix(word, best)
+}
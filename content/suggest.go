@@ -0,0 +1,300 @@
+package content
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/portfolio/db"
+	"github.com/lithammer/fuzzysearch/fuzzy"
+)
+
+const (
+	suggestCacheTTL     = time.Minute
+	suggestCacheSize    = 256
+	suggestFuzzyTitles  = 500
+	suggestTagCandidate = 200
+)
+
+type Suggestions struct {
+	Titles []TitleSuggestion `json:"titles"`
+	Tags   []string          `json:"tags"`
+}
+
+type TitleSuggestion struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	Fuzzy bool   `json:"fuzzy,omitempty"`
+}
+
+type suggestEntry struct {
+	value   Suggestions
+	expires time.Time
+}
+
+var (
+	suggestMu    sync.Mutex
+	suggestCache = map[string]suggestEntry{}
+)
+
+// Suggest returns up to limit titles and tags of the items listed in the
+// given language, which falls back along LanguageChain as listings do,
+// that start with what the user has typed so far. Titles come from an FTS prefix
+// query; if that finds too few, titles within a small edit distance are
+// added so that typos still produce suggestions. Results are cached for a
+// minute since the same prefixes are requested over and over.
+func Suggest(prefix, language string, limit int) (Suggestions, error) {
+	key := fmt.Sprintf("%s|%d|%s", language, limit, strings.ToLower(prefix))
+
+	suggestMu.Lock()
+	if e, ok := suggestCache[key]; ok && time.Now().Before(e.expires) {
+		suggestMu.Unlock()
+		return e.value, nil
+	}
+	suggestMu.Unlock()
+
+	words := queryWords(prefix)
+	s := Suggestions{Titles: []TitleSuggestion{}, Tags: []string{}}
+	if len(words) == 0 {
+		return s, nil
+	}
+
+	titles, err := suggestTitles(words, language, limit)
+	if err != nil {
+		return s, err
+	}
+	s.Titles = titles
+
+	if len(s.Titles) < limit {
+		more, err := suggestFuzzy(words, language, limit-len(s.Titles), s.Titles)
+		if err != nil {
+			return s, err
+		}
+		s.Titles = append(s.Titles, more...)
+	}
+
+	s.Tags, err = suggestTags(words[len(words)-1], language, limit)
+	if err != nil {
+		return s, err
+	}
+
+	suggestMu.Lock()
+	if len(suggestCache) >= suggestCacheSize {
+		now := time.Now()
+		for k, e := range suggestCache {
+			if now.After(e.expires) {
+				delete(suggestCache, k)
+			}
+		}
+		if len(suggestCache) >= suggestCacheSize {
+			suggestCache = map[string]suggestEntry{}
+		}
+	}
+	suggestCache[key] = suggestEntry{value: s, expires: time.Now().Add(suggestCacheTTL)}
+	suggestMu.Unlock()
+
+	return s, nil
+}
+
+// suggestTitles looks for the titles in each language of the chain in
+// turn, so that titles in the language itself come first.
+func suggestTitles(words []string, language string, limit int) ([]TitleSuggestion, error) {
+	chain := fallbackChain(language)
+	titles := []TitleSuggestion{}
+	for _, l := range LanguageChain(language) {
+		if len(titles) == limit {
+			break
+		}
+		more, err := suggestTitlesIn(words, l, chain, limit-len(titles))
+		if err != nil {
+			return nil, err
+		}
+		titles = append(titles, more...)
+	}
+	return titles, nil
+}
+
+// suggestTitlesIn returns the titles in language l among the items listed
+// for chain.
+func suggestTitlesIn(words []string, l, chain string, limit int) ([]TitleSuggestion, error) {
+	table := "blog_search"
+	if isStemmed(l) {
+		table = "blog_search_stem"
+		var stems []string
+		for _, w := range words {
+			stems = append(stems, stemTokens(l, w)...)
+		}
+		words = stems
+	}
+	// Stemming can leave nothing to match, e.g. a lone Uzbek hard sign.
+	if len(words) == 0 {
+		return []TitleSuggestion{}, nil
+	}
+
+	parts := make([]string, 0, len(words))
+	for i, w := range words {
+		p := `title : "` + w + `"`
+		if i == len(words)-1 || isStemmed(l) {
+			p += "*"
+		}
+		parts = append(parts, p)
+	}
+
+	query := `
+		SELECT d.id, d.title
+		FROM ` + table + `
+		JOIN blog_data d ON d.id = ` + table + `.rowid
+		WHERE ` + table + ` MATCH ?
+		  AND d.language = ?
+		  AND d.id IN (` + preferredIDs() + `)
+		ORDER BY bm25(` + table + `) ASC
+		LIMIT ?;
+	`
+	rows, err := db.DB.QueryContext(context.Background(), query,
+		strings.Join(parts, " AND "), l, chain, chain, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	defer rows.Close()
+
+	titles := []TitleSuggestion{}
+	for rows.Next() {
+		var t TitleSuggestion
+		if err := rows.Scan(&t.ID, &t.Title); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		titles = append(titles, t)
+	}
+	return titles, rows.Err()
+}
+
+// suggestFuzzy matches every typed word against the beginnings of the
+// title words, allowing one typo in short words and two in longer ones.
+func suggestFuzzy(words []string, language string, limit int, exclude []TitleSuggestion) ([]TitleSuggestion, error) {
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT id, title FROM blog_data
+		WHERE id IN (`+preferredIDs()+`)
+		ORDER BY created_at DESC
+		LIMIT ?;
+	`, fallbackChain(language), fallbackChain(language), suggestFuzzyTitles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	defer rows.Close()
+
+	seen := map[int64]bool{}
+	for _, t := range exclude {
+		seen[t.ID] = true
+	}
+
+	type ranked struct {
+		TitleSuggestion
+		distance int
+	}
+	var found []ranked
+
+	for rows.Next() {
+		var t TitleSuggestion
+		if err := rows.Scan(&t.ID, &t.Title); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if seen[t.ID] {
+			continue
+		}
+		if d, ok := typoDistance(words, queryWords(t.Title)); ok {
+			t.Fuzzy = true
+			found = append(found, ranked{t, d})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].distance < found[j].distance
+	})
+
+	out := []TitleSuggestion{}
+	for _, f := range found {
+		if len(out) == limit {
+			break
+		}
+		out = append(out, f.TitleSuggestion)
+	}
+	return out, nil
+}
+
+func typoDistance(words, titleWords []string) (int, bool) {
+	total := 0
+	for _, w := range words {
+		wr := []rune(w)
+		allowed := 1
+		if len(wr) > 4 {
+			allowed = 2
+		}
+
+		best := -1
+		for _, tw := range titleWords {
+			tr := []rune(tw)
+			if len(tr) > len(wr) {
+				tr = tr[:len(wr)]
+			}
+			d := fuzzy.LevenshteinDistance(w, string(tr))
+			if best < 0 || d < best {
+				best = d
+			}
+		}
+		if best < 0 || best > allowed {
+			return 0, false
+		}
+		total += best
+	}
+	return total, true
+}
+
+func suggestTags(prefix, language string, limit int) ([]string, error) {
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT meta_tag FROM blog_data
+		WHERE id IN (`+preferredIDs("meta_tag IS NOT NULL AND meta_tag != ''")+`)
+		ORDER BY created_at DESC
+		LIMIT ?;
+	`, fallbackChain(language), fallbackChain(language), suggestTagCandidate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var tags string
+		if err := rows.Scan(&tags); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		for _, t := range splitTags(tags) {
+			if strings.HasPrefix(t, prefix) {
+				counts[t]++
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tags := make([]string, 0, len(counts))
+	for t := range counts {
+		tags = append(tags, t)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if counts[tags[i]] != counts[tags[j]] {
+			return counts[tags[i]] > counts[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	if len(tags) > limit {
+		tags = tags[:limit]
+	}
+	return tags, nil
+}
@@ -0,0 +1,202 @@
+package content
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"example.com/portfolio/db"
+	"example.com/portfolio/validation"
+)
+
+// defaultFallback is used when LANGUAGE_FALLBACK is not set.
+const defaultFallback = "uz:ru,en;ru:en"
+
+// LanguageChain returns language followed by the languages served in its
+// place when an item has no published translation in it, most preferred
+// first. The chains come from LANGUAGE_FALLBACK, e.g. "uz:ru,en;ru:en"
+// (the default); "off" disables fallback.
+func LanguageChain(language string) []string {
+	chain := []string{language}
+	spec := os.Getenv("LANGUAGE_FALLBACK")
+	if spec == "" {
+		spec = defaultFallback
+	}
+	if strings.EqualFold(spec, "off") {
+		return chain
+	}
+
+	for _, entry := range strings.Split(spec, ";") {
+		from, to, ok := strings.Cut(entry, ":")
+		if !ok || strings.TrimSpace(from) != language {
+			continue
+		}
+		for _, l := range strings.Split(to, ",") {
+			l = strings.TrimSpace(l)
+			if contains(Languages, l) && !contains(chain, l) {
+				chain = append(chain, l)
+			}
+		}
+	}
+	return chain
+}
+
+// Translation returns the ID of the published item of id's translation
+// group that is in the most preferred language of LanguageChain(language).
+// If the group has none in the chain, id itself is returned.
+func Translation(id int64, language string) (int64, error) {
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT id, language FROM blog_data
+		WHERE status = 'published'
+		  AND COALESCE(translation_group, id) =
+		      (SELECT COALESCE(translation_group, id) FROM blog_data WHERE id = ?)
+		ORDER BY id
+	`, id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get translations: %w", err)
+	}
+	defer rows.Close()
+
+	byLanguage := map[string]int64{}
+	for rows.Next() {
+		var (
+			tid int64
+			l   string
+		)
+		if err := rows.Scan(&tid, &l); err != nil {
+			return 0, fmt.Errorf("failed to scan translation: %w", err)
+		}
+		if _, ok := byLanguage[l]; !ok || tid == id {
+			byLanguage[l] = tid
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, l := range LanguageChain(language) {
+		if tid, ok := byLanguage[l]; ok {
+			return tid, nil
+		}
+	}
+	return id, nil
+}
+
+// joinGroup resolves c.TranslationGroup, which may be the ID of any item
+// of a group, to the ID of that group. Zero, or c's own ID, puts c in a
+// group of its own. If other items are grouped under c's ID, Update moves
+// them along with c, so the languages of all of them are checked: a group
+// holds at most one item per language. Add and Update run it in the
+// transaction that saves c.
+func (c *Content) joinGroup(ctx context.Context, q queryer) error {
+	if c.TranslationGroup == 0 || c.TranslationGroup == c.ID {
+		c.TranslationGroup = c.ID
+		return nil
+	}
+
+	var group int64
+	err := q.QueryRowContext(ctx,
+		"SELECT COALESCE(translation_group, id) FROM blog_data WHERE id = ?", c.TranslationGroup).
+		Scan(&group)
+	if err == sql.ErrNoRows {
+		return invalidFields(validation.Errors{{Field: "translation_group", Reason: "must be the ID of an existing content item"}})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get translation group: %w", err)
+	}
+
+	if group == c.ID {
+		c.TranslationGroup = group
+		return nil
+	}
+
+	var (
+		taken    int64
+		language string
+	)
+	err = q.QueryRowContext(ctx, `
+		SELECT id, language FROM blog_data
+		WHERE COALESCE(translation_group, id) = ? AND id != ?
+		  AND (language = ? OR language IN (
+		      SELECT language FROM blog_data WHERE translation_group = ? AND id != ?))
+		LIMIT 1
+	`, group, c.ID, c.Language, c.ID, c.ID).Scan(&taken, &language)
+	if err == nil {
+		var errs validation.Errors
+		errs.Addf("translation_group", "already has a %s translation (content %d)", language, taken)
+		return invalidFields(errs)
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to check translation group: %w", err)
+	}
+
+	c.TranslationGroup = group
+	return nil
+}
+
+// queryer is *sql.DB or *sql.Tx.
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// fallbackChain is LanguageChain as a ",uz,ru,en," list for SQL instr(),
+// or "" for no language filter.
+func fallbackChain(language string) string {
+	if language == "" {
+		return ""
+	}
+	return "," + strings.Join(LanguageChain(language), ",") + ","
+}
+
+// preferredIDs is a subquery for the IDs of the published items listed in
+// a language: of each translation group that meets conds, the item in the
+// most preferred language of the chain. Its arguments are the
+// fallbackChain twice, then those of conds.
+func preferredIDs(conds ...string) string {
+	where := ""
+	for _, c := range conds {
+		where += " AND (" + c + ")"
+	}
+	return `SELECT id FROM (
+		SELECT id, ROW_NUMBER() OVER (
+			PARTITION BY COALESCE(translation_group, id)
+			ORDER BY instr(?, ',' || language || ','), id
+		) AS preference
+		FROM blog_data
+		WHERE status = 'published' AND instr(?, ',' || language || ',') > 0` + where + `
+	) WHERE preference = 1`
+}
+
+// PreferredTranslations returns the items of contents listed in language,
+// as preferredIDs selects them, keeping their order. Items in another
+// language have RequestedLanguage set. TranslationGroup must be loaded.
+func PreferredTranslations(contents []Content, language string) []Content {
+	chain := LanguageChain(language)
+	best := map[int64]int{}
+	for _, c := range contents {
+		rank := slices.Index(chain, c.Language)
+		if rank < 0 {
+			continue
+		}
+		if r, ok := best[c.TranslationGroup]; !ok || rank < r {
+			best[c.TranslationGroup] = rank
+		}
+	}
+
+	out := []Content{}
+	for _, c := range contents {
+		r, ok := best[c.TranslationGroup]
+		if !ok || chain[r] != c.Language {
+			continue
+		}
+		delete(best, c.TranslationGroup)
+		if c.Language != language {
+			c.RequestedLanguage = language
+		}
+		out = append(out, c)
+	}
+	return out
+}
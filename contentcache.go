@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http"
+
+	"example.com/portfolio/content"
+	"example.com/portfolio/validation"
+	"github.com/gin-gonic/gin"
+)
+
+// cacheStats godoc
+// @Summary      Read cache statistics
+// @Description  Returns size, hit, miss, eviction and expiry counts of the content read caches
+// @Security     TokenAuth
+// @Tags         cache
+// @Produce      json
+// @Success      200  {object}  map[string]cache.Stats
+// @Router       /cache/stats [get]
+func cacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, content.CacheStats())
+}
+
+// purgeCache godoc
+// @Summary      Purge the read cache
+// @Description  Drops every cached content item and page
+// @Security     TokenAuth
+// @Tags         cache
+// @Produce      json
+// @Success      200  {object}  map[string]string  "Cache purged"
+// @Router       /cache/purge [post]
+func purgeCache(c *gin.Context) {
+	content.PurgeCache()
+	c.JSON(http.StatusOK, gin.H{"message": "Cache purged successfully"})
+}
+
+type cacheSwitch struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// switchCache godoc
+// @Summary      Enable or disable the read cache
+// @Description  Turns the content read cache on or off until the next restart. CONTENT_CACHE=off disables it at startup.
+// @Security     TokenAuth
+// @Tags         cache
+// @Accept       json
+// @Produce      json
+// @Param        switch  body      cacheSwitch  true  "Whether the cache is enabled"
+// @Success      200     {object}  map[string]cache.Stats
+// @Failure      400     {object}  map[string]string  "Invalid input"
+// @Failure      422     {object}  map[string]interface{}  "Validation failed; lists each failing field"
+// @Router       /cache/enabled [put]
+func switchCache(c *gin.Context) {
+	var in cacheSwitch
+	if !bindJSON(c, &in) {
+		return
+	}
+	if in.Enabled == nil {
+		invalidInput(c, validation.Errors{{Field: "enabled", Reason: "is required"}})
+		return
+	}
+
+	content.SetCacheEnabled(*in.Enabled)
+	c.JSON(http.StatusOK, content.CacheStats())
+}
@@ -34,6 +34,14 @@ func Initdb() {
 	infotable()
 	content()
 	signUp()
+	searchLog()
+	comments()
+	reactions()
+	projects()
+	series()
+	gallery()
+	webhooks()
+	newsletter()
 }
 
 func infotable() {
@@ -79,6 +87,13 @@ func content() {
 	);
 
 
+	-- Stemmed ru/uz text, maintained from Go by the content package
+	CREATE VIRTUAL TABLE IF NOT EXISTS blog_search_stem USING fts5(
+		title,
+		body,
+		tokenize='unicode61 remove_diacritics 2'
+	);
+
 	CREATE TRIGGER IF NOT EXISTS blog_data_ai AFTER INSERT ON blog_data BEGIN
 		INSERT INTO blog_search(rowid, title, body)
 		VALUES (new.id, new.title, new.body);
@@ -114,6 +129,25 @@ func content() {
 		log.Printf("⚠️ Could not sync blog_search with blog_data: %v", err)
 	}
 
+	addColumn("blog_data", "version", "INTEGER NOT NULL DEFAULT 1")
+	addColumn("blog_data", "updated_at", "TEXT")
+	addColumn("blog_data", "status", "TEXT NOT NULL DEFAULT 'published'")
+	// NULL puts an item in a translation group of its own
+	addColumn("blog_data", "translation_group", "INTEGER")
+	// Bumped when data served with an item, such as its gallery or series
+	// navigation, changes; version only counts edits of the item itself
+	addColumn("blog_data", "attached_version", "INTEGER NOT NULL DEFAULT 0")
+
+	_, err = DB.ExecContext(ctx, "CREATE INDEX IF NOT EXISTS idx_blog_data_translation_group ON blog_data(translation_group)")
+	if err != nil {
+		log.Printf("⚠️ Could not create translation group index: %v", err)
+	}
+
+	_, err = DB.ExecContext(ctx, "UPDATE blog_data SET updated_at = created_at WHERE updated_at IS NULL")
+	if err != nil {
+		log.Printf("⚠️ Could not backfill blog_data.updated_at: %v", err)
+	}
+
 	fmt.Println("✅ Content tables and FTS index initialized successfully.")
 }
 
@@ -131,3 +165,270 @@ func signUp() {
 		log.Fatalf("❌ Could not create signUp table: %v", err)
 	}
 }
+
+func searchLog() {
+	query := `
+	CREATE TABLE IF NOT EXISTS search_log(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		query TEXT NOT NULL,
+		language TEXT,
+		category TEXT,
+		results INTEGER NOT NULL,
+		created_at TEXT DEFAULT (datetime('now'))
+	);
+
+	CREATE INDEX IF NOT EXISTS search_log_created_at ON search_log(created_at);
+
+	CREATE TABLE IF NOT EXISTS search_clicks(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		query TEXT NOT NULL,
+		content_id INTEGER NOT NULL,
+		created_at TEXT DEFAULT (datetime('now'))
+	);
+	`
+
+	_, err := DB.ExecContext(context.Background(), query)
+	if err != nil {
+		log.Fatalf("❌ Could not create search log tables: %v", err)
+	}
+}
+
+func comments() {
+	query := `
+	CREATE TABLE IF NOT EXISTS comments(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		content_id INTEGER NOT NULL,
+		parent_id INTEGER,
+		name TEXT NOT NULL,
+		body TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		ip TEXT,
+		created_at TEXT DEFAULT (datetime('now'))
+	);
+
+	CREATE INDEX IF NOT EXISTS comments_content_id ON comments(content_id, status);
+	`
+
+	_, err := DB.ExecContext(context.Background(), query)
+	if err != nil {
+		log.Fatalf("❌ Could not create comments table: %v", err)
+	}
+}
+
+func reactions() {
+	query := `
+	CREATE TABLE IF NOT EXISTS reactions(
+		content_id INTEGER NOT NULL,
+		reaction TEXT NOT NULL,
+		fingerprint TEXT NOT NULL,
+		created_at TEXT DEFAULT (datetime('now')),
+		PRIMARY KEY (content_id, reaction, fingerprint)
+	);
+
+	-- Counts changes to the reactions of each item, for conditional requests
+	CREATE TABLE IF NOT EXISTS reaction_changes(
+		content_id INTEGER PRIMARY KEY,
+		changes INTEGER NOT NULL DEFAULT 0,
+		changed_at TEXT
+	);
+
+	CREATE TRIGGER IF NOT EXISTS reactions_ai AFTER INSERT ON reactions BEGIN
+		INSERT OR IGNORE INTO reaction_changes (content_id) VALUES (new.content_id);
+		UPDATE reaction_changes SET changes = changes + 1, changed_at = datetime('now')
+		WHERE content_id = new.content_id;
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS reactions_ad AFTER DELETE ON reactions BEGIN
+		INSERT OR IGNORE INTO reaction_changes (content_id) VALUES (old.content_id);
+		UPDATE reaction_changes SET changes = changes + 1, changed_at = datetime('now')
+		WHERE content_id = old.content_id;
+	END;
+	`
+
+	_, err := DB.ExecContext(context.Background(), query)
+	if err != nil {
+		log.Fatalf("❌ Could not create reactions table: %v", err)
+	}
+}
+
+func projects() {
+	query := `
+	CREATE TABLE IF NOT EXISTS project_data(
+		content_id INTEGER PRIMARY KEY,
+		repo_url TEXT,
+		demo_url TEXT,
+		tech_stack TEXT,
+		role TEXT,
+		client TEXT,
+		start_date TEXT,
+		end_date TEXT
+	);
+	`
+
+	_, err := DB.ExecContext(context.Background(), query)
+	if err != nil {
+		log.Fatalf("❌ Could not create project_data table: %v", err)
+	}
+}
+
+func series() {
+	query := `
+	CREATE TABLE IF NOT EXISTS series(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		title TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		created_at TEXT DEFAULT (datetime('now'))
+	);
+	`
+
+	_, err := DB.ExecContext(context.Background(), query)
+	if err != nil {
+		log.Fatalf("❌ Could not create series table: %v", err)
+	}
+
+	query = `
+	CREATE TABLE IF NOT EXISTS series_items(
+		series_id INTEGER NOT NULL,
+		content_id INTEGER PRIMARY KEY,
+		position INTEGER NOT NULL
+	);
+	`
+
+	_, err = DB.ExecContext(context.Background(), query)
+	if err != nil {
+		log.Fatalf("❌ Could not create series_items table: %v", err)
+	}
+
+	_, err = DB.ExecContext(context.Background(),
+		"CREATE INDEX IF NOT EXISTS idx_series_items_series ON series_items(series_id, position);")
+	if err != nil {
+		log.Fatalf("❌ Could not create series_items index: %v", err)
+	}
+}
+
+func gallery() {
+	query := `
+	CREATE TABLE IF NOT EXISTS content_images(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		content_id INTEGER NOT NULL,
+		url TEXT NOT NULL,
+		alt TEXT NOT NULL DEFAULT '',
+		caption TEXT NOT NULL DEFAULT '',
+		position INTEGER NOT NULL
+	);
+	`
+
+	_, err := DB.ExecContext(context.Background(), query)
+	if err != nil {
+		log.Fatalf("❌ Could not create content_images table: %v", err)
+	}
+
+	_, err = DB.ExecContext(context.Background(),
+		"CREATE INDEX IF NOT EXISTS idx_content_images_content ON content_images(content_id, position);")
+	if err != nil {
+		log.Fatalf("❌ Could not create content_images index: %v", err)
+	}
+}
+
+func webhooks() {
+	query := `
+	CREATE TABLE IF NOT EXISTS webhooks(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		url TEXT NOT NULL,
+		secret TEXT NOT NULL,
+		events TEXT NOT NULL,
+		active INTEGER NOT NULL DEFAULT 1,
+		created_at TEXT DEFAULT (datetime('now'))
+	);
+
+	CREATE TABLE IF NOT EXISTS webhook_deliveries(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		webhook_id INTEGER NOT NULL,
+		event TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at TEXT,
+		response_status INTEGER,
+		response_body TEXT,
+		error TEXT,
+		redelivery_of INTEGER,
+		created_at TEXT DEFAULT (datetime('now')),
+		delivered_at TEXT
+	);
+
+	CREATE INDEX IF NOT EXISTS webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
+	CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook ON webhook_deliveries(webhook_id, id);
+	`
+
+	_, err := DB.ExecContext(context.Background(), query)
+	if err != nil {
+		log.Fatalf("❌ Could not create webhook tables: %v", err)
+	}
+}
+
+func newsletter() {
+	query := `
+	CREATE TABLE IF NOT EXISTS subscribers(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		email TEXT NOT NULL UNIQUE,
+		language TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		ip TEXT,
+		created_at TEXT DEFAULT (datetime('now')),
+		confirmation_sent_at TEXT,
+		confirmed_at TEXT,
+		unsubscribed_at TEXT
+	);
+
+	CREATE INDEX IF NOT EXISTS subscribers_language ON subscribers(language, status);
+
+	-- Every call to subscribe, for the daily limit per IP
+	CREATE TABLE IF NOT EXISTS subscribe_attempts(
+		ip TEXT NOT NULL,
+		created_at TEXT DEFAULT (datetime('now'))
+	);
+
+	CREATE INDEX IF NOT EXISTS subscribe_attempts_ip ON subscribe_attempts(ip, created_at);
+
+	-- Links in newsletter mails; only a hash of the token is stored
+	CREATE TABLE IF NOT EXISTS unsubscribe_tokens(
+		token_hash TEXT PRIMARY KEY,
+		subscriber_id INTEGER NOT NULL,
+		expires_at TEXT NOT NULL
+	);
+
+	-- Content already announced, so that it is mailed only once. sent_at
+	-- stays NULL while the mails are being sent.
+	CREATE TABLE IF NOT EXISTS newsletter_sent(
+		content_id INTEGER PRIMARY KEY,
+		recipients INTEGER NOT NULL DEFAULT 0,
+		sent_at TEXT DEFAULT (datetime('now'))
+	);
+	`
+
+	_, err := DB.ExecContext(context.Background(), query)
+	if err != nil {
+		log.Fatalf("❌ Could not create newsletter tables: %v", err)
+	}
+}
+
+// addColumn adds a column to a table created by an earlier version of the
+// schema, unless it is already there.
+func addColumn(table, column, definition string) {
+	ctx := context.Background()
+	var n int
+	err := DB.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&n)
+	if err != nil {
+		log.Fatalf("❌ Could not inspect %s table: %v", table, err)
+	}
+	if n > 0 {
+		return
+	}
+
+	_, err = DB.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	if err != nil {
+		log.Fatalf("❌ Could not add %s.%s column: %v", table, column, err)
+	}
+}
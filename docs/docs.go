@@ -24,34 +24,74 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/blog/{id}": {
+        "/archive": {
             "get": {
-                "description": "Returns one content item (blog or project) by its ID",
+                "description": "Returns the number of published items of a language and category per year and per month, newest first, for archive navigation. Items not translated into the language count in the language they fall back to, as in GET /blogs/{page}. A year or month is listed with GET /blogs/{page}?from=...\u0026to=....",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "content"
+                    "Content"
                 ],
-                "summary": "Get single content by ID",
+                "summary": "Archive counts",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Blog ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "enum": [
+                            "en",
+                            "ru",
+                            "uz"
+                        ],
+                        "type": "string",
+                        "description": "Language (default en)",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "blog",
+                            "project"
+                        ],
+                        "type": "string",
+                        "description": "Category (default blog)",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified time of a cached copy",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Counts per year, each with its months",
                         "schema": {
-                            "$ref": "#/definitions/content.Content"
+                            "type": "object",
+                            "additionalProperties": true
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching policy, configurable with CACHE_CONTROL_ARCHIVE"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Weak validator of the archive"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "Not modified"
+                    },
                     "400": {
-                        "description": "Invalid blog ID",
+                        "description": "Invalid parameters",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -59,8 +99,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Blog not found",
+                    "500": {
+                        "description": "Failed to fetch archive",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -71,21 +111,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/blogs/{page}": {
+        "/blog/{id}": {
             "get": {
-                "description": "Returns paginated blogs with optional filters for language, category, and title.",
+                "description": "Returns one content item (blog or project) by its ID. Drafts and archived items are only returned to an admin. With language, the item's published translation into that language is returned instead, or into the next language of its LANGUAGE_FALLBACK chain; requested_language is then set if the language served differs.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Content"
+                    "content"
                 ],
-                "summary": "Get blogs",
+                "summary": "Get single content by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
+                        "description": "Blog ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
@@ -96,37 +136,59 @@ const docTemplate = `{
                             "uz"
                         ],
                         "type": "string",
-                        "description": "Language filter (default: en)",
+                        "description": "Preferred language",
                         "name": "language",
                         "in": "query"
                     },
                     {
-                        "enum": [
-                            "blog",
-                            "project"
-                        ],
                         "type": "string",
-                        "description": "Category filter",
-                        "name": "category",
+                        "description": "Search query the item was opened from, recorded for click-through statistics",
+                        "name": "q",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Search by blog title",
-                        "name": "title",
-                        "in": "query"
+                        "description": "ETag of a cached copy",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified time of a cached copy",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Blogs retrieved successfully",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/content.Content"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching policy, configurable with CACHE_CONTROL_BLOG"
+                            },
+                            "Content-Language": {
+                                "type": "string",
+                                "description": "Language of the content served"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "State of the content including its reactions, also accepted by If-Match on update and delete"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Time of the last change to the content"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "Not modified"
+                    },
                     "400": {
-                        "description": "Invalid parameters",
+                        "description": "Invalid blog ID or language",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -135,7 +197,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "No blogs found",
+                        "description": "Blog not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -144,7 +206,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Failed to fetch blog",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -155,28 +217,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/delete/{id}": {
-            "delete": {
-                "security": [
-                    {
-                        "TokenAuth": []
-                    }
-                ],
-                "description": "deletes blog",
-                "consumes": [
-                    "application/json"
-                ],
+        "/blog/{id}/comments": {
+            "get": {
+                "description": "Returns the approved comments of a content item as a thread",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "content"
+                    "comments"
                 ],
-                "summary": "for deleting the blog",
+                "summary": "List comments",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID number to fetch",
+                        "description": "Blog ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -184,16 +238,14 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Blog deleted successfully",
+                        "description": "Comments",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Could not find blog with this ID",
+                        "description": "Invalid blog ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -202,7 +254,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to delete blog",
+                        "description": "Failed to fetch comments",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -211,11 +263,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/login": {
+            },
             "post": {
-                "description": "Admin's login page",
+                "description": "Adds a comment or a reply to an approved comment. New comments wait for moderation.",
                 "consumes": [
                     "application/json"
                 ],
@@ -223,23 +273,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin"
+                    "comments"
                 ],
-                "summary": "login",
+                "summary": "Submit a comment",
                 "parameters": [
                     {
-                        "description": "Admin login",
-                        "name": "admin",
+                        "type": "integer",
+                        "description": "Blog ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Name, body and optional parent_id",
+                        "name": "comment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/admin.Login"
+                            "$ref": "#/definitions/comments.Comment"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Logged in successfully",
+                    "201": {
+                        "description": "Comment submitted for moderation",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -247,8 +311,24 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Invalid input",
+                    "404": {
+                        "description": "Blog not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Daily comment limit reached",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -257,7 +337,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Could not generate token",
+                        "description": "Server or database error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -268,19 +348,63 @@ const docTemplate = `{
                 }
             }
         },
-        "/portfolio": {
-            "get": {
-                "description": "Returns Hello World message",
+        "/blog/{id}/gallery": {
+            "put": {
+                "description": "Sets the display order of the gallery of a content item. Every image must be listed exactly once.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "general"
+                    "gallery"
+                ],
+                "summary": "Reorder a gallery",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Blog ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Image IDs in display order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.galleryOrder"
+                        }
+                    }
                 ],
-                "summary": "Hello endpoint",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Gallery",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to reorder gallery",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -288,17 +412,15 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/post": {
-            "post": {
+                },
                 "security": [
                     {
                         "TokenAuth": []
                     }
-                ],
-                "description": "Upload image and publish content",
+                ]
+            },
+            "post": {
+                "description": "Appends one or more images to the gallery of a content item. The n-th alt and caption values belong to the n-th image. The cover image is not affected.",
                 "consumes": [
                     "multipart/form-data"
                 ],
@@ -306,61 +428,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "content"
+                    "gallery"
                 ],
-                "summary": "Publish new blog or project",
+                "summary": "Upload gallery images",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Language (e.g., uz)",
-                        "name": "language",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Type (blog/project)",
-                        "name": "type",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "Blog ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "file",
-                        "description": "Image file",
-                        "name": "image",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Title",
-                        "name": "title",
+                        "description": "Image files",
+                        "name": "images",
                         "in": "formData",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Body",
-                        "name": "body",
-                        "in": "formData",
-                        "required": true
+                        "description": "Alt text, repeated once per image",
+                        "name": "alt",
+                        "in": "formData"
                     },
                     {
                         "type": "string",
-                        "description": "Meta tags",
-                        "name": "meta_tag",
+                        "description": "Caption, repeated once per image",
+                        "name": "caption",
                         "in": "formData"
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created",
+                        "description": "Uploaded images",
                         "schema": {
-                            "$ref": "#/definitions/content.Content"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Blog not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -368,8 +485,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Failed to upload images",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -377,12 +501,17 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/request": {
-            "post": {
-                "description": "Accepts user portfolio request data, saves it, and notifies admin via Telegram",
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/blog/{id}/gallery/{image}": {
+            "put": {
+                "description": "Changes the alt text and caption of a gallery image",
                 "consumes": [
                     "application/json"
                 ],
@@ -390,23 +519,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "requests"
+                    "gallery"
                 ],
-                "summary": "Submit a portfolio request",
+                "summary": "Edit a gallery image",
                 "parameters": [
                     {
-                        "description": "Portfolio request data",
-                        "name": "request",
+                        "type": "integer",
+                        "description": "Blog ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Image ID",
+                        "name": "image",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Alt text and caption",
+                        "name": "text",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/info.About"
+                            "$ref": "#/definitions/main.galleryText"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Request successfully submitted",
+                        "description": "Gallery",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -421,8 +564,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "429": {
-                        "description": "Daily request limit reached",
+                    "404": {
+                        "description": "Image not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -430,8 +573,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
-                        "description": "Server or database error",
+                        "description": "Failed to update image",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -439,12 +589,84 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Removes an image from the gallery of a content item",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gallery"
+                ],
+                "summary": "Remove a gallery image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Blog ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Image ID",
+                        "name": "image",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Gallery",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Image not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete image",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
             }
         },
-        "/signup": {
+        "/blog/{id}/reactions": {
             "post": {
-                "description": "Register sign up in order to login",
+                "description": "Adds an anonymous reaction (like, love, clap or fire). Each visitor can leave each reaction once per item.",
                 "consumes": [
                     "application/json"
                 ],
@@ -452,23 +674,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin"
+                    "reactions"
                 ],
-                "summary": "Sign up admin",
+                "summary": "React to content",
                 "parameters": [
                     {
-                        "description": "Admin sign up",
-                        "name": "admin",
+                        "type": "integer",
+                        "description": "Blog ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reaction",
+                        "name": "reaction",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/admin.Address"
+                            "$ref": "#/definitions/main.reactionInput"
                         }
                     }
                 ],
                 "responses": {
+                    "200": {
+                        "description": "Already reacted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "201": {
-                        "description": "Signed up successfully",
+                        "description": "Reaction added",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -476,8 +719,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "The sign up must contain username, email, password",
+                    "404": {
+                        "description": "Blog not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -485,8 +728,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
-                        "description": "Could not sign up. Try again later",
+                        "description": "Failed to save reaction",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -497,45 +747,102 @@ const docTemplate = `{
                 }
             }
         },
-        "/update/{id}": {
-            "put": {
-                "security": [
+        "/blog/{id}/reactions/{reaction}": {
+            "delete": {
+                "description": "Removes the visitor's own reaction from a content item",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reactions"
+                ],
+                "summary": "Remove a reaction",
+                "parameters": [
                     {
-                        "TokenAuth": []
+                        "type": "integer",
+                        "description": "Blog ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "like",
+                            "love",
+                            "clap",
+                            "fire"
+                        ],
+                        "type": "string",
+                        "description": "Reaction",
+                        "name": "reaction",
+                        "in": "path",
+                        "required": true
                     }
                 ],
-                "description": "deletes blog",
-                "consumes": [
-                    "application/json"
-                ],
+                "responses": {
+                    "200": {
+                        "description": "Reaction removed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to remove reaction",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/blog/{id}/related": {
+            "get": {
+                "description": "Returns \"read next\" items as listed in the item's language, falling back along LANGUAGE_FALLBACK like GET /blogs/{page}, ranked by text similarity and shared tags. Translations of the item are left out.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "content"
                 ],
-                "summary": "for deleting the blog contents",
+                "summary": "Get related content",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID number to fetch",
+                        "description": "Blog ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Blog data",
-                        "name": "content",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/content.Content"
-                        }
+                        "type": "integer",
+                        "description": "Maximum number of items (1-20, default 4)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Blog updated successfully",
+                        "description": "Related content",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid blog ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -543,8 +850,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Could not find blog with this ID",
+                    "404": {
+                        "description": "Blog not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -553,7 +860,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Invalid request body",
+                        "description": "Failed to fetch related content",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -563,55 +870,3391 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "admin.Address": {
-            "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
-            "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
-                },
-                "username": {
-                    "type": "string"
-                }
-            }
-        },
-        "admin.Login": {
-            "type": "object",
-            "required": [
-                "login",
-                "password"
-            ],
-            "properties": {
-                "login": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
-                }
-            }
         },
-        "content.Content": {
-            "type": "object",
-            "properties": {
-                "body": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "featured": {
+        "/blogs/{page}": {
+            "get": {
+                "description": "Returns paginated blogs with optional filters for language, category, and title. Without a title, items not translated into the language are listed in the first available language of the LANGUAGE_FALLBACK chain (default uz→ru→en, ru→en) and carry requested_language.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Content"
+                ],
+                "summary": "Get blogs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "ru",
+                            "uz"
+                        ],
+                        "type": "string",
+                        "description": "Language, with fallback (default: en)",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "blog",
+                            "project"
+                        ],
+                        "type": "string",
+                        "description": "Category filter",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query: words, quoted phrases, prefix*, -exclusions, title: or body: scoping",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only projects using this technology",
+                        "name": "tech",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only items created in or after this year or month (YYYY or YYYY-MM)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only items created in or before this year or month (YYYY or YYYY-MM)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Marker inserted before matched terms: \u003cmark\u003e, \u003cb\u003e, \u003cstrong\u003e, \u003cem\u003e or plain text (default \u003cmark\u003e)",
+                        "name": "highlight_open",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Marker inserted after matched terms, closing the opening tag (default \u003c/mark\u003e)",
+                        "name": "highlight_close",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of tokens in the result snippet (1-64, default 24)",
+                        "name": "snippet_length",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified time of a cached copy",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Blogs retrieved successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching policy, configurable with CACHE_CONTROL_BLOGS"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Weak validator of the listing"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No blogs found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid from or to; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/bulk": {
+            "post": {
+                "description": "Applies operations (delete, set_featured, set_status, add_tag, remove_tag) in order to every listed content item in one transaction. If any item fails, nothing is changed and the per-item results explain why. delete cannot be combined with other operations.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "content"
+                ],
+                "summary": "Bulk content operations",
+                "parameters": [
+                    {
+                        "description": "Content IDs and operations",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.bulkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Per-item results",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid operations",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Missing ids or operations, or batch rolled back; per-item results",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to apply operations",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/cache/enabled": {
+            "put": {
+                "description": "Turns the content read cache on or off until the next restart. CONTENT_CACHE=off disables it at startup.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cache"
+                ],
+                "summary": "Enable or disable the read cache",
+                "parameters": [
+                    {
+                        "description": "Whether the cache is enabled",
+                        "name": "switch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.cacheSwitch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/cache.Stats"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/cache/purge": {
+            "post": {
+                "description": "Drops every cached content item and page",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cache"
+                ],
+                "summary": "Purge the read cache",
+                "responses": {
+                    "200": {
+                        "description": "Cache purged",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/cache/stats": {
+            "get": {
+                "description": "Returns size, hit, miss, eviction and expiry counts of the content read caches",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cache"
+                ],
+                "summary": "Read cache statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/cache.Stats"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/comments": {
+            "get": {
+                "description": "Returns comments with the given moderation status, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "List comments for moderation",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "spam"
+                        ],
+                        "type": "string",
+                        "description": "Moderation status (default pending)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Comments",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch comments",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/comments/{id}": {
+            "delete": {
+                "description": "Deletes a comment and all replies below it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Delete a comment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Comment deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid comment ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete comment",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/comments/{id}/status": {
+            "put": {
+                "description": "Sets the moderation status of a comment. Only approved comments are shown publicly.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Moderate a comment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status (pending, approved or spam)",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.commentStatus"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Comment updated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update comment",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/delete/{id}": {
+            "delete": {
+                "description": "deletes blog",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "content"
+                ],
+                "summary": "for deleting the blog",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID number to fetch",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the revision being deleted",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Blog deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Could not find blog with this ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Content has been modified; returns the current version",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete blog",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/export": {
+            "get": {
+                "description": "Returns all published content grouped by language and type, for prerendering the frontend without the API. Each language lists what its listings show, including items that fall back to another language. json is a single versioned bundle; tree is a zip of manifest.json, series.json and \u003clanguage\u003e/\u003ctype\u003e/\u003cid\u003e.json; hugo is a zip of Hugo Markdown pages under content/\u003clanguage\u003e/\u003ctype\u003e/ with config/_default/languages.yaml.",
+                "produces": [
+                    "application/json",
+                    "application/zip"
+                ],
+                "tags": [
+                    "content"
+                ],
+                "summary": "Export published content",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "tree",
+                            "hugo"
+                        ],
+                        "type": "string",
+                        "description": "Export format (default json)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified time of a cached copy",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/export.Bundle"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching policy, configurable with CACHE_CONTROL_EXPORT"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Weak validator of the export"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Invalid format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to export content",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/import": {
+            "post": {
+                "description": "Creates or updates content from Markdown files with YAML front matter (language, type, title, tags, featured, status, cover, date, and optionally id). Files may be uploaded individually or as zip archives; images referenced from a document, including its cover, must be part of the upload and are uploaded to Cloudinary. An item is updated when the front matter has its id or an item with the same language, type and title exists.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "content"
+                ],
+                "summary": "Import Markdown content",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Markdown files, images or zip archives",
+                        "name": "files",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Per-file results",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid upload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/import/{source}": {
+            "post": {
+                "description": "Maps the posts, tags, dates and featured images of a WordPress WXR (XML) or Ghost (JSON) export onto content and converts their HTML to Markdown. Nothing is written unless dry_run is false; the report lists what was, or would be, created, updated and skipped. Posts are matched to existing content by language, type and title.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "content"
+                ],
+                "summary": "Import a WordPress or Ghost export",
+                "parameters": [
+                    {
+                        "enum": [
+                            "wordpress",
+                            "ghost"
+                        ],
+                        "type": "string",
+                        "description": "Export format",
+                        "name": "source",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Export file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report, default true",
+                        "name": "dry_run",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language when the export names none, default en",
+                        "name": "language",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Content type, default blog",
+                        "name": "type",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "URL of the old site, to resolve relative links",
+                        "name": "site_url",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/importer.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid export",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Admin's login page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "login",
+                "parameters": [
+                    {
+                        "description": "Admin login",
+                        "name": "admin",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.Login"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Logged in successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Could not generate token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/newsletter/confirm": {
+            "get": {
+                "description": "Target of the link in the confirmation mail",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "newsletter"
+                ],
+                "summary": "Confirm a newsletter subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token from the confirmation link",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscription confirmed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or expired link",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Subscription is not awaiting confirmation",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Could not confirm",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/newsletter/subscribe": {
+            "post": {
+                "description": "Mails a confirmation link to the address; new posts in the chosen language are only sent once it is confirmed. The response is the same whether or not the address is already subscribed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "newsletter"
+                ],
+                "summary": "Subscribe to the newsletter",
+                "parameters": [
+                    {
+                        "description": "Email and language (en, ru or uz)",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/newsletter.Subscription"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Confirmation mail sent",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Daily subscription limit reached",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Could not subscribe",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/newsletter/subscribers": {
+            "get": {
+                "description": "Returns subscribers, newest first, with the number of subscribers per status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "newsletter"
+                ],
+                "summary": "List newsletter subscribers",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "confirmed",
+                            "unsubscribed"
+                        ],
+                        "type": "string",
+                        "description": "Subscription status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "ru",
+                            "uz"
+                        ],
+                        "type": "string",
+                        "description": "Language",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscribers and counts",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch subscribers",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/newsletter/unsubscribe": {
+            "get": {
+                "description": "Target of the link in every newsletter mail. Shows a page asking to confirm, whose form posts to the same URL; opening the link does not unsubscribe.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "newsletter"
+                ],
+                "summary": "Show the unsubscribe page",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token from the unsubscribe link",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Confirmation page",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or expired link",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not check the link",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Posted by the unsubscribe page and by mail clients that support one-click unsubscribing (RFC 8058). Answers with a page when the client accepts HTML, otherwise with JSON.",
+                "produces": [
+                    "application/json",
+                    "text/html"
+                ],
+                "tags": [
+                    "newsletter"
+                ],
+                "summary": "Unsubscribe from the newsletter",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token from the unsubscribe link",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Unsubscribed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or expired link",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Could not unsubscribe",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio": {
+            "get": {
+                "description": "Returns Hello World message",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "general"
+                ],
+                "summary": "Hello endpoint",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    }
+                }
+            }
+        },
+        "/post": {
+            "post": {
+                "description": "Upload image and publish content",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "content"
+                ],
+                "summary": "Publish new blog or project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language (e.g., uz)",
+                        "name": "language",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type (blog/project)",
+                        "name": "type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image file",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Title",
+                        "name": "title",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Body",
+                        "name": "body",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Meta tags",
+                        "name": "meta_tag",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "published",
+                            "draft",
+                            "archived"
+                        ],
+                        "type": "string",
+                        "description": "Publication status (default published)",
+                        "name": "status",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of an item this is a translation of",
+                        "name": "translation_group",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Project repository URL (type=project)",
+                        "name": "repo_url",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Project live demo URL (type=project)",
+                        "name": "demo_url",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated technologies (type=project)",
+                        "name": "tech_stack",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role in the project (type=project)",
+                        "name": "role",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client (type=project)",
+                        "name": "client",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date, YYYY-MM or YYYY-MM-DD (type=project)",
+                        "name": "start_date",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, YYYY-MM or YYYY-MM-DD (type=project)",
+                        "name": "end_date",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/content.Content"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/request": {
+            "post": {
+                "description": "Accepts user portfolio request data, saves it, and notifies admin via Telegram",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "requests"
+                ],
+                "summary": "Submit a portfolio request",
+                "parameters": [
+                    {
+                        "description": "Portfolio request data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/info.About"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Request successfully submitted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Daily request limit reached",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server or database error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "description": "Searches all languages and categories and returns one page of matches with counts per language, type, featured flag and tag.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Content"
+                ],
+                "summary": "Faceted search",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query: words, quoted phrases, prefix*, -exclusions, title: or body: scoping",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "ru",
+                            "uz"
+                        ],
+                        "type": "string",
+                        "description": "Language filter",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "blog",
+                            "project"
+                        ],
+                        "type": "string",
+                        "description": "Category filter",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "true",
+                            "false"
+                        ],
+                        "type": "string",
+                        "description": "Featured filter",
+                        "name": "featured",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag filter",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Marker inserted before matched terms: \u003cmark\u003e, \u003cb\u003e, \u003cstrong\u003e, \u003cem\u003e or plain text (default \u003cmark\u003e)",
+                        "name": "highlight_open",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Marker inserted after matched terms, closing the opening tag (default \u003c/mark\u003e)",
+                        "name": "highlight_close",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of tokens in the result snippet (1-64, default 24)",
+                        "name": "snippet_length",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/content.SearchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/search/report": {
+            "get": {
+                "description": "Returns the most frequent search queries, queries without results and click-through by content ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Search analytics report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reporting window in days (1-365, default 30)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum rows per section (1-100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/analytics.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/series": {
+            "get": {
+                "description": "Returns all series, newest first, without their items",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "List series",
+                "responses": {
+                    "200": {
+                        "description": "Series",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch series",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates an empty series. Parts are added with PUT /series/{id}/items.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Create a series",
+                "parameters": [
+                    {
+                        "description": "Title and description",
+                        "name": "series",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/content.Series"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/content.Series"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create series",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/series/{id}": {
+            "get": {
+                "description": "Returns a series with its parts in reading order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Get a series",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/content.Series"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid series ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Series not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch series",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates the title and description of a series",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Update a series",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Title and description",
+                        "name": "series",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/content.Series"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Series updated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Series not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update series",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Deletes a series. Its parts are kept as standalone content.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Delete a series",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Series deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid series ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Series not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete series",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/series/{id}/items": {
+            "put": {
+                "description": "Replaces the parts of a series with the given content IDs, in reading order. A content item can belong to only one series.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Set the parts of a series",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ordered content IDs",
+                        "name": "items",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.seriesItems"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/content.Series"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Series not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update series",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Adds a content item as the last part of a series",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Append a part to a series",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Content ID",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.seriesItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/content.Series"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Series not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update series",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/series/{id}/items/{content}": {
+            "delete": {
+                "description": "Removes a content item from a series. The remaining parts keep their order.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Remove a part from a series",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Content ID",
+                        "name": "content",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/content.Series"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Series not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update series",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/signup": {
+            "post": {
+                "description": "Register sign up in order to login",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Sign up admin",
+                "parameters": [
+                    {
+                        "description": "Admin sign up",
+                        "name": "admin",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.Address"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Signed up successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Username or email is already taken",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Could not sign up. Try again later",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/suggest": {
+            "get": {
+                "description": "Returns titles and tags matching what the user has typed so far, tolerating small typos in titles. Titles of items not translated into the language are suggested in the language they fall back to.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Content"
+                ],
+                "summary": "Search suggestions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Typed text (2-64 characters)",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "ru",
+                            "uz"
+                        ],
+                        "type": "string",
+                        "description": "Language (default: en)",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum suggestions per kind (1-10, default 5)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/content.Suggestions"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/update/{id}": {
+            "put": {
+                "description": "deletes blog",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "content"
+                ],
+                "summary": "for deleting the blog contents",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID number to fetch",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the revision being edited",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Blog data",
+                        "name": "content",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/content.Content"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Blog updated successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Content has been modified; returns the current version",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update blog",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "Applies a JSON merge patch (RFC 7396) to a content item. Only the fields present in the patch change; null removes an optional field. The image can only be set to an uploaded URL. Returns the list of changed fields.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "content"
+                ],
+                "summary": "Partially update content",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Blog ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the revision being edited",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Merge patch",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/content.Content"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Changed fields and the updated content",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patch",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Blog not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Content has been modified; returns the current version",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported content type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update blog",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks",
+                "responses": {
+                    "200": {
+                        "description": "Webhooks, without their secrets",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch webhooks",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Registers an endpoint for events (content.created, content.updated, content.deleted, request.created). content.updated is also sent when the gallery, series or comments of an item change; request.created carries only the request's ID, name and time. Each event is POSTed as JSON with an X-Webhook-Signature header: t=\u003cunix time\u003e,v1=\u003chex HMAC-SHA256 of \"\u003cunix time\u003e.\u003cbody\u003e\"\u003e, keyed with the secret returned here, which is not shown again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Register a webhook",
+                "parameters": [
+                    {
+                        "description": "URL, events and active flag (default true)",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/webhooks.Webhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/webhooks.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create webhook",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/{id}": {
+            "put": {
+                "description": "Changes the URL and events of a webhook and, if given, switches it on or off. Deliveries queued while it is off are sent once it is on again. The secret is kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "URL, events and active flag",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/webhooks.Webhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webhooks.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed; lists each failing field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update webhook",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Deletes a webhook and its delivery log",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete webhook",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Returns the deliveries of a webhook, newest first, with the number of attempts, the last response or error and, for pending ones, the time of the next attempt.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhook delivery log",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "delivered",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Delivery status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deliveries",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch deliveries",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery}/redeliver": {
+            "post": {
+                "description": "Queues the payload of a delivery again as a new delivery, e.g. after fixing the endpoint. The payload keeps its event id, so receivers can recognize the duplicate.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Redeliver a webhook event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "delivery",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/webhooks.Delivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to queue delivery",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/{id}/ping": {
+            "post": {
+                "description": "Queues a ping event for the webhook, even if it is switched off. The outcome appears in its delivery log.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Test a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/webhooks.Delivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to queue ping",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "TokenAuth": []
+                    }
+                ]
+            }
+        }
+    },
+    "definitions": {
+        "admin.Address": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.Login": {
+            "type": "object",
+            "properties": {
+                "login": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "analytics.ClickStat": {
+            "type": "object",
+            "properties": {
+                "clicks": {
+                    "type": "integer"
+                },
+                "content_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "analytics.QueryStat": {
+            "type": "object",
+            "properties": {
+                "avg_results": {
+                    "type": "number"
+                },
+                "click_through": {
+                    "type": "number"
+                },
+                "clicks": {
+                    "type": "integer"
+                },
+                "last_searched": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "searches": {
+                    "type": "integer"
+                }
+            }
+        },
+        "analytics.Report": {
+            "type": "object",
+            "properties": {
+                "clicks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/analytics.ClickStat"
+                    }
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "top_queries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/analytics.QueryStat"
+                    }
+                },
+                "zero_results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/analytics.QueryStat"
+                    }
+                }
+            }
+        },
+        "cache.Stats": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "evictions": {
+                    "type": "integer"
+                },
+                "expired": {
+                    "type": "integer"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "ttl": {
+                    "type": "string"
+                }
+            }
+        },
+        "comments.Comment": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "content_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "replies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/comments.Comment"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "content.BulkOp": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "content.Content": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "featured": {
+                    "type": "string"
+                },
+                "gallery": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.GalleryImage"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "meta_tag": {
+                    "type": "string"
+                },
+                "project": {
+                    "$ref": "#/definitions/content.Project"
+                },
+                "reactions": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "requested_language": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "series": {
+                    "$ref": "#/definitions/content.SeriesNav"
+                },
+                "snippet": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "title_highlight": {
+                    "type": "string"
+                },
+                "translation_group": {
+                    "description": "TranslationGroup is shared by the translations of one item; it is\nthe ID of one of them. RequestedLanguage is set when the item is\nserved in place of a missing translation into that language.",
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "content.Facets": {
+            "type": "object",
+            "properties": {
+                "featured": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "language": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "tag": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "type": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "content.GalleryImage": {
+            "type": "object",
+            "properties": {
+                "alt": {
+                    "type": "string"
+                },
+                "caption": {
+                    "type": "string"
+                },
+                "content_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "content.Project": {
+            "type": "object",
+            "properties": {
+                "client": {
+                    "type": "string"
+                },
+                "demo_url": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "repo_url": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "tech_stack": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "content.SearchResult": {
+            "type": "object",
+            "properties": {
+                "contents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.Content"
+                    }
+                },
+                "facets": {
+                    "$ref": "#/definitions/content.Facets"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "content.Series": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.SeriesLink"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "content.SeriesLink": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "part": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "content.SeriesNav": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "next": {
+                    "$ref": "#/definitions/content.SeriesLink"
+                },
+                "part": {
+                    "type": "integer"
+                },
+                "prev": {
+                    "$ref": "#/definitions/content.SeriesLink"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "content.Suggestions": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "titles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.TitleSuggestion"
+                    }
+                }
+            }
+        },
+        "content.TitleSuggestion": {
+            "type": "object",
+            "properties": {
+                "fuzzy": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "export.Bundle": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "object",
+                        "additionalProperties": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/content.Content"
+                            }
+                        }
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "format": {
+                    "type": "integer"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "revision": {
+                    "type": "string"
+                },
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.Series"
+                    }
+                }
+            }
+        },
+        "importer.Report": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/importer.ReportItem"
+                    }
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "importer.ReportItem": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "error": {
                     "type": "string"
                 },
+                "featured": {
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -621,28 +4264,31 @@ const docTemplate = `{
                 "language": {
                     "type": "string"
                 },
-                "meta_tag": {
+                "source_id": {
                     "type": "string"
                 },
-                "score": {
-                    "type": "number"
+                "status": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "string"
                 },
                 "title": {
                     "type": "string"
                 },
                 "type": {
                     "type": "string"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
         "info.About": {
             "type": "object",
-            "required": [
-                "lastname",
-                "name",
-                "phone",
-                "telegram"
-            ],
             "properties": {
                 "createdAt": {
                     "type": "string"
@@ -669,6 +4315,169 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "main.bulkRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.BulkOp"
+                    }
+                }
+            }
+        },
+        "main.cacheSwitch": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "main.commentStatus": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.galleryOrder": {
+            "type": "object",
+            "properties": {
+                "image_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "main.galleryText": {
+            "type": "object",
+            "properties": {
+                "alt": {
+                    "type": "string"
+                },
+                "caption": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.reactionInput": {
+            "type": "object",
+            "properties": {
+                "reaction": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.seriesItem": {
+            "type": "object",
+            "properties": {
+                "content_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "main.seriesItems": {
+            "type": "object",
+            "properties": {
+                "content_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "newsletter.Subscription": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                }
+            }
+        },
+        "webhooks.Delivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "redelivery_of": {
+                    "type": "integer"
+                },
+                "response_body": {
+                    "type": "string"
+                },
+                "response_status": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "webhooks.Webhook": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"example.com/portfolio/cache"
+	"example.com/portfolio/content"
+	"example.com/portfolio/export"
+	"example.com/portfolio/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+// exports keeps the latest export of each format; keys include the
+// content state, so a stale export is never served.
+var exports = cache.New[string, []byte](len(export.Formats), 10*time.Minute)
+
+// exportContent godoc
+// @Summary      Export published content
+// @Description  Returns all published content grouped by language and type, for prerendering the frontend without the API. Each language lists what its listings show, including items that fall back to another language. json is a single versioned bundle; tree is a zip of manifest.json, series.json and <language>/<type>/<id>.json; hugo is a zip of Hugo Markdown pages under content/<language>/<type>/ with config/_default/languages.yaml.
+// @Tags         content
+// @Produce      json
+// @Produce      application/zip
+// @Param        format             query   string  false  "Export format (default json)"  Enums(json, tree, hugo)
+// @Param        If-None-Match      header  string  false  "ETag of a cached copy"
+// @Param        If-Modified-Since  header  string  false  "Last-Modified time of a cached copy"
+// @Success      200  {object}  export.Bundle
+// @Header       200  {string}  ETag           "Weak validator of the export"
+// @Header       200  {string}  Cache-Control  "Caching policy, configurable with CACHE_CONTROL_EXPORT"
+// @Success      304  "Not modified"
+// @Failure      400  {object}  map[string]string  "Invalid format"
+// @Failure      500  {object}  map[string]string  "Failed to export content"
+// @Router       /export [get]
+func exportContent(c *gin.Context) {
+	format := c.DefaultQuery("format", export.FormatJSON)
+	valid := false
+	for _, f := range export.Formats {
+		valid = valid || f == format
+	}
+	if !valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be one of " + strings.Join(export.Formats, ", ")})
+		return
+	}
+
+	etag, modified, err := content.ListState(export.StateKey)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export content"})
+		return
+	}
+	if middlewares.NotModified(c, etag, modified) {
+		return
+	}
+
+	data, err := exports.GetOrLoad(format+etag, func() ([]byte, error) {
+		b, err := export.Build(etag)
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if format == export.FormatJSON {
+			err = export.WriteJSON(&buf, b)
+			return buf.Bytes(), err
+		}
+		zw := zip.NewWriter(&buf)
+		if err := export.Write(format, b, export.Zip(zw)); err != nil {
+			return nil, err
+		}
+		err = zw.Close()
+		return buf.Bytes(), err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export content"})
+		return
+	}
+
+	if format == export.FormatJSON {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="content-%s.zip"`, format))
+	c.Data(http.StatusOK, "application/zip", data)
+}
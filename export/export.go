@@ -0,0 +1,161 @@
+// Package export writes all published content to static files, so that
+// the frontend can be prerendered without the API running.
+package export
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"example.com/portfolio/content"
+)
+
+// FormatVersion is increased whenever the layout of the bundle or the tree
+// changes in a way readers have to know about.
+const FormatVersion = 1
+
+const (
+	FormatJSON = "json"
+	FormatTree = "tree"
+	FormatHugo = "hugo"
+)
+
+var Formats = []string{FormatJSON, FormatTree, FormatHugo}
+
+// StateKey is the key of content.ListState for exports.
+const StateKey = "export"
+
+// Bundle is all published content, grouped by language and type. Each
+// language lists what its listings show: items not translated into it
+// appear in the language they fall back to, with requested_language set.
+// Count is the number of distinct items.
+type Bundle struct {
+	Format      int                                     `json:"format"`
+	GeneratedAt string                                  `json:"generated_at"`
+	Revision    string                                  `json:"revision"`
+	Count       int                                     `json:"count"`
+	Content     map[string]map[string][]content.Content `json:"content"`
+	Series      []content.Series                        `json:"series"`
+}
+
+// Build loads the bundle. etag is what content.ListState(StateKey)
+// returned before; the bundle's Revision is derived from it, so that it
+// identifies the state of the content as the ETag of the listings does.
+func Build(etag string) (Bundle, error) {
+	contents, err := content.Published()
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	b := Bundle{
+		Format:      FormatVersion,
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		Revision:    strings.Trim(strings.TrimPrefix(etag, "W/"), `"`),
+		Count:       len(contents),
+		Content:     map[string]map[string][]content.Content{},
+		Series:      []content.Series{},
+	}
+	for _, lang := range content.Languages {
+		byType := map[string][]content.Content{}
+		for _, c := range content.PreferredTranslations(contents, lang) {
+			byType[c.Type] = append(byType[c.Type], c)
+		}
+		for _, list := range byType {
+			sort.SliceStable(list, func(i, j int) bool {
+				if list[i].CreatedAt != list[j].CreatedAt {
+					return list[i].CreatedAt > list[j].CreatedAt
+				}
+				return list[i].ID > list[j].ID
+			})
+		}
+		if len(byType) > 0 {
+			b.Content[lang] = byType
+		}
+	}
+
+	list, err := content.ListSeries()
+	if err != nil {
+		return Bundle{}, err
+	}
+	for _, s := range list {
+		full, err := content.GetSeries(s.ID)
+		if err != nil {
+			return Bundle{}, err
+		}
+		if len(full.Items) > 0 {
+			b.Series = append(b.Series, full)
+		}
+	}
+	return b, nil
+}
+
+// Write writes the bundle in the given format. JSON is a single file
+// named bundle.json; the other formats are file trees.
+func Write(format string, b Bundle, t Target) error {
+	switch format {
+	case FormatJSON:
+		return writeJSONFile(t, "bundle.json", b)
+	case FormatTree:
+		return writeTree(b, t)
+	case FormatHugo:
+		return writeHugo(b, t)
+	}
+	return fmt.Errorf("unknown format %q: must be one of %s", format, strings.Join(Formats, ", "))
+}
+
+// WriteJSON writes the bundle as a single JSON document.
+func WriteJSON(w io.Writer, b Bundle) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// Target receives the files of an export, named with slash-separated
+// relative paths.
+type Target interface {
+	WriteFile(name string, data []byte) error
+}
+
+// Dir writes an export below a directory on disk.
+type Dir string
+
+func (d Dir) WriteFile(name string, data []byte) error {
+	p := filepath.Join(string(d), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, 0o644)
+}
+
+// Zip writes an export into a zip archive.
+func Zip(zw *zip.Writer) Target {
+	return zipTarget{zw}
+}
+
+type zipTarget struct {
+	zw *zip.Writer
+}
+
+func (z zipTarget) WriteFile(name string, data []byte) error {
+	w, err := z.zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// date converts a stored timestamp to RFC 3339.
+func date(stored string) string {
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", stored, time.UTC)
+	if err != nil {
+		return stored
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,152 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"example.com/portfolio/content"
+	"go.yaml.in/yaml/v3"
+)
+
+// hugoPage is the front matter of an exported page. Fields Hugo does not
+// know are available to templates as .Params.
+type hugoPage struct {
+	Title      string             `yaml:"title"`
+	Date       string             `yaml:"date"`
+	Lastmod    string             `yaml:"lastmod,omitempty"`
+	Slug       string             `yaml:"slug"`
+	Tags       []string           `yaml:"tags,omitempty"`
+	Series     []string           `yaml:"series,omitempty"`
+	Images     []string           `yaml:"images,omitempty"`
+	ID         int64              `yaml:"id"`
+	Featured   bool               `yaml:"featured"`
+	SeriesPart int                `yaml:"series_part,omitempty"`
+	Project    *hugoProject       `yaml:"project,omitempty"`
+	Gallery    []hugoGalleryImage `yaml:"gallery,omitempty"`
+}
+
+type hugoProject struct {
+	RepoURL   string   `yaml:"repo_url,omitempty"`
+	DemoURL   string   `yaml:"demo_url,omitempty"`
+	TechStack []string `yaml:"tech_stack,omitempty"`
+	Role      string   `yaml:"role,omitempty"`
+	Client    string   `yaml:"client,omitempty"`
+	StartDate string   `yaml:"start_date,omitempty"`
+	EndDate   string   `yaml:"end_date,omitempty"`
+}
+
+type hugoGalleryImage struct {
+	URL     string `yaml:"url"`
+	Alt     string `yaml:"alt,omitempty"`
+	Caption string `yaml:"caption,omitempty"`
+}
+
+// hugoLanguage is an entry of config/_default/languages.yaml.
+type hugoLanguage struct {
+	ContentDir string `yaml:"contentDir"`
+	Weight     int    `yaml:"weight"`
+}
+
+// writeHugo writes one Markdown page per item to
+// content/<language>/<type>/<id>-<slug>.md, and a languages.yaml that
+// points each Hugo language at its content directory. Series become the
+// "series" taxonomy, which has to be enabled in the site configuration.
+func writeHugo(b Bundle, t Target) error {
+	languages := map[string]hugoLanguage{}
+	for i, lang := range sortedKeys(b.Content) {
+		languages[lang] = hugoLanguage{ContentDir: "content/" + lang, Weight: i + 1}
+
+		for _, typ := range sortedKeys(b.Content[lang]) {
+			for _, c := range b.Content[lang][typ] {
+				page, err := hugoMarkdown(c)
+				if err != nil {
+					return fmt.Errorf("content %d: %w", c.ID, err)
+				}
+				name := fmt.Sprintf("content/%s/%s/%d-%s.md", lang, typ, c.ID, slug(c.Title))
+				if err := t.WriteFile(name, page); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	config, err := yaml.Marshal(languages)
+	if err != nil {
+		return err
+	}
+	return t.WriteFile("config/_default/languages.yaml", config)
+}
+
+func hugoMarkdown(c content.Content) ([]byte, error) {
+	page := hugoPage{
+		Title:    c.Title,
+		Date:     date(c.CreatedAt),
+		Slug:     slug(c.Title),
+		ID:       c.ID,
+		Featured: c.Featured == "true",
+	}
+	if p := c.Project; p != nil {
+		page.Project = &hugoProject{
+			RepoURL: p.RepoURL, DemoURL: p.DemoURL, TechStack: p.TechStack, Role: p.Role,
+			Client: p.Client, StartDate: p.StartDate, EndDate: p.EndDate,
+		}
+	}
+	if c.UpdatedAt != "" && c.UpdatedAt != c.CreatedAt {
+		page.Lastmod = date(c.UpdatedAt)
+	}
+	for _, tag := range strings.Split(c.Tag, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			page.Tags = append(page.Tags, tag)
+		}
+	}
+	if c.Image != "" {
+		page.Images = []string{c.Image}
+	}
+	if c.Series != nil {
+		page.Series = []string{c.Series.Title}
+		page.SeriesPart = c.Series.Part
+	}
+	for _, g := range c.Gallery {
+		page.Gallery = append(page.Gallery, hugoGalleryImage{URL: g.URL, Alt: g.Alt, Caption: g.Caption})
+	}
+
+	front, err := yaml.Marshal(page)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(front)
+	buf.WriteString("---\n\n")
+	buf.WriteString(strings.TrimRight(c.Body, "\n"))
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// slug makes a URL path segment from a title. Letters of any script are
+// kept, so Russian and Uzbek titles keep readable slugs.
+func slug(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+		if b.Len() >= 80 {
+			break
+		}
+	}
+	s := strings.Trim(b.String(), "-")
+	if s == "" {
+		return "untitled"
+	}
+	return s
+}
@@ -0,0 +1,81 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// manifest is the index of a tree export. Each item is written to
+// <language>/<type>/<id>.json and listed here with the fields needed to
+// render listings.
+type manifest struct {
+	Format      int                                 `json:"format"`
+	GeneratedAt string                              `json:"generated_at"`
+	Revision    string                              `json:"revision"`
+	Count       int                                 `json:"count"`
+	Content     map[string]map[string][]manifestRef `json:"content"`
+	Series      string                              `json:"series"`
+}
+
+type manifestRef struct {
+	ID        int64  `json:"id"`
+	Title     string `json:"title"`
+	Image     string `json:"image"`
+	Tags      string `json:"meta_tag,omitempty"`
+	Featured  string `json:"featured,omitempty"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+	Path      string `json:"path"`
+}
+
+func writeTree(b Bundle, t Target) error {
+	m := manifest{
+		Format:      b.Format,
+		GeneratedAt: b.GeneratedAt,
+		Revision:    b.Revision,
+		Count:       b.Count,
+		Content:     map[string]map[string][]manifestRef{},
+		Series:      "series.json",
+	}
+
+	for _, lang := range sortedKeys(b.Content) {
+		m.Content[lang] = map[string][]manifestRef{}
+		for _, typ := range sortedKeys(b.Content[lang]) {
+			refs := []manifestRef{}
+			for _, c := range b.Content[lang][typ] {
+				path := fmt.Sprintf("%s/%s/%d.json", lang, typ, c.ID)
+				if err := writeJSONFile(t, path, c); err != nil {
+					return err
+				}
+				refs = append(refs, manifestRef{
+					ID: c.ID, Title: c.Title, Image: c.Image, Tags: c.Tag, Featured: c.Featured,
+					CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt, Path: path,
+				})
+			}
+			m.Content[lang][typ] = refs
+		}
+	}
+
+	if err := writeJSONFile(t, "series.json", b.Series); err != nil {
+		return err
+	}
+	return writeJSONFile(t, "manifest.json", m)
+}
+
+func writeJSONFile(t Target, name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return t.WriteFile(name, append(data, '\n'))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
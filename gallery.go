@@ -0,0 +1,255 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"example.com/portfolio/content"
+	"example.com/portfolio/validation"
+	"example.com/portfolio/webhooks"
+	"github.com/gin-gonic/gin"
+)
+
+const maxGalleryUpload = 20
+
+// uploadGallery godoc
+// @Summary      Upload gallery images
+// @Description  Appends one or more images to the gallery of a content item. The n-th alt and caption values belong to the n-th image. The cover image is not affected.
+// @Security     TokenAuth
+// @Tags         gallery
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id       path      int     true   "Blog ID"
+// @Param        images   formData  file    true   "Image files"
+// @Param        alt      formData  string  false  "Alt text, repeated once per image"
+// @Param        caption  formData  string  false  "Caption, repeated once per image"
+// @Success      201      {object}  map[string]interface{}  "Uploaded images"
+// @Failure      400      {object}  map[string]string       "Invalid input"
+// @Failure      404      {object}  map[string]string       "Blog not found"
+// @Failure      422      {object}  map[string]interface{}  "Validation failed; lists each failing field"
+// @Failure      500      {object}  map[string]string       "Failed to upload images"
+// @Router       /blog/{id}/gallery [post]
+func uploadGallery(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blog ID"})
+		return
+	}
+
+	if _, err := content.GetById(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["images"]) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one image file is required"})
+		return
+	}
+	files := form.File["images"]
+	if len(files) > maxGalleryUpload {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d images can be uploaded at once", maxGalleryUpload)})
+		return
+	}
+	alts := form.Value["alt"]
+	captions := form.Value["caption"]
+
+	// Check the text of every image before uploading any, so that a long
+	// caption does not leave the gallery half uploaded. Errors name the
+	// image by its position, e.g. images.2.caption.
+	pending := make([]content.GalleryImage, len(files))
+	var errs validation.Errors
+	for i := range files {
+		g := content.GalleryImage{ContentID: id}
+		if i < len(alts) {
+			g.Alt = alts[i]
+		}
+		if i < len(captions) {
+			g.Caption = captions[i]
+		}
+		var imageErrs validation.Errors
+		if err := g.Validate(); errors.As(err, &imageErrs) {
+			errs.Nest(fmt.Sprintf("images.%d", i), imageErrs)
+		}
+		pending[i] = g
+	}
+	if len(errs) > 0 {
+		invalidInput(c, errs)
+		return
+	}
+
+	images := []content.GalleryImage{}
+	for i, file := range files {
+		filename := fmt.Sprintf("uploads/%s", file.Filename)
+		if err := c.SaveUploadedFile(file, filename); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save image"})
+			return
+		}
+
+		g := pending[i]
+		g.URL = filename
+		if err := g.Add(); err != nil {
+			if len(images) > 0 {
+				emitChanged(webhooks.EventContentUpdated, []int64{id})
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload images", "uploaded": images})
+			return
+		}
+		images = append(images, g)
+	}
+
+	emitChanged(webhooks.EventContentUpdated, []int64{id})
+	c.JSON(http.StatusCreated, gin.H{"images": images})
+}
+
+type galleryOrder struct {
+	ImageIDs []int64 `json:"image_ids"`
+}
+
+// reorderGallery godoc
+// @Summary      Reorder a gallery
+// @Description  Sets the display order of the gallery of a content item. Every image must be listed exactly once.
+// @Security     TokenAuth
+// @Tags         gallery
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int           true  "Blog ID"
+// @Param        order  body      galleryOrder  true  "Image IDs in display order"
+// @Success      200    {object}  map[string]interface{}  "Gallery"
+// @Failure      400    {object}  map[string]string       "Invalid input"
+// @Failure      422    {object}  map[string]interface{}  "Validation failed; lists each failing field"
+// @Failure      500    {object}  map[string]string       "Failed to reorder gallery"
+// @Router       /blog/{id}/gallery [put]
+func reorderGallery(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blog ID"})
+		return
+	}
+
+	var in galleryOrder
+	if !bindJSON(c, &in) {
+		return
+	}
+	if in.ImageIDs == nil {
+		invalidInput(c, validation.Errors{{Field: "image_ids", Reason: "is required"}})
+		return
+	}
+
+	if err := content.ReorderGallery(id, in.ImageIDs); err != nil {
+		if errors.Is(err, content.ErrInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder gallery"})
+		return
+	}
+
+	respondGallery(c, id)
+}
+
+type galleryText struct {
+	Alt     string `json:"alt"`
+	Caption string `json:"caption"`
+}
+
+// editGalleryImage godoc
+// @Summary      Edit a gallery image
+// @Description  Changes the alt text and caption of a gallery image
+// @Security     TokenAuth
+// @Tags         gallery
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int          true  "Blog ID"
+// @Param        image  path      int          true  "Image ID"
+// @Param        text   body      galleryText  true  "Alt text and caption"
+// @Success      200    {object}  map[string]interface{}  "Gallery"
+// @Failure      400    {object}  map[string]string       "Invalid input"
+// @Failure      404    {object}  map[string]string       "Image not found"
+// @Failure      422    {object}  map[string]interface{}  "Validation failed; lists each failing field"
+// @Failure      500    {object}  map[string]string       "Failed to update image"
+// @Router       /blog/{id}/gallery/{image} [put]
+func editGalleryImage(c *gin.Context) {
+	id, imageID, ok := galleryParams(c)
+	if !ok {
+		return
+	}
+
+	var in galleryText
+	if !bindJSON(c, &in) {
+		return
+	}
+
+	if err := content.UpdateGalleryImage(id, imageID, in.Alt, in.Caption); err != nil {
+		switch {
+		case errors.Is(err, content.ErrImageNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Image not found"})
+		case errors.Is(err, content.ErrInvalid):
+			invalidInput(c, err)
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update image"})
+		}
+		return
+	}
+
+	respondGallery(c, id)
+}
+
+// deleteGalleryImage godoc
+// @Summary      Remove a gallery image
+// @Description  Removes an image from the gallery of a content item
+// @Security     TokenAuth
+// @Tags         gallery
+// @Produce      json
+// @Param        id     path      int  true  "Blog ID"
+// @Param        image  path      int  true  "Image ID"
+// @Success      200    {object}  map[string]interface{}  "Gallery"
+// @Failure      400    {object}  map[string]string       "Invalid input"
+// @Failure      404    {object}  map[string]string       "Image not found"
+// @Failure      500    {object}  map[string]string       "Failed to delete image"
+// @Router       /blog/{id}/gallery/{image} [delete]
+func deleteGalleryImage(c *gin.Context) {
+	id, imageID, ok := galleryParams(c)
+	if !ok {
+		return
+	}
+
+	if err := content.DeleteGalleryImage(id, imageID); err != nil {
+		if errors.Is(err, content.ErrImageNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Image not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete image"})
+		return
+	}
+
+	respondGallery(c, id)
+}
+
+func galleryParams(c *gin.Context) (int64, int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blog ID"})
+		return 0, 0, false
+	}
+	imageID, err := strconv.ParseInt(c.Param("image"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid image ID"})
+		return 0, 0, false
+	}
+	return id, imageID, true
+}
+
+// respondGallery announces a changed gallery and returns it.
+func respondGallery(c *gin.Context, id int64) {
+	emitChanged(webhooks.EventContentUpdated, []int64{id})
+
+	images, err := content.GetGallery(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch gallery"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"images": images})
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"example.com/portfolio/importer"
+	"example.com/portfolio/webhooks"
+	"github.com/gin-gonic/gin"
+)
+
+// importContent godoc
+// @Summary      Import Markdown content
+// @Description  Creates or updates content from Markdown files with YAML front matter (language, type, title, tags, featured, status, cover, date, and optionally id). Files may be uploaded individually or as zip archives; images referenced from a document, including its cover, must be part of the upload and are uploaded to Cloudinary. An item is updated when the front matter has its id or an item with the same language, type and title exists.
+// @Security     TokenAuth
+// @Tags         content
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        files  formData  file  true  "Markdown files, images or zip archives"
+// @Success      200    {object}  map[string]interface{}  "Per-file results"
+// @Failure      400    {object}  map[string]string       "Invalid upload"
+// @Router       /import [post]
+func importContent(c *gin.Context) {
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["files"]) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one file is required"})
+		return
+	}
+
+	files := importer.NewFiles()
+	for _, fh := range form.File["files"] {
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Could not read %s", fh.Filename)})
+			return
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Could not read %s", fh.Filename)})
+			return
+		}
+		if err := files.Add(fh.Filename, data); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if len(files.Documents()) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No Markdown documents found in the upload"})
+		return
+	}
+
+	var im importer.Importer
+	results := im.Import(files)
+
+	var created, updated []int64
+	for _, r := range results {
+		switch r.Action {
+		case importer.ActionCreated:
+			created = append(created, r.ID)
+		case importer.ActionUpdated:
+			updated = append(updated, r.ID)
+		}
+	}
+	emitChanged(webhooks.EventContentCreated, created)
+	emitChanged(webhooks.EventContentUpdated, updated)
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+// importExport godoc
+// @Summary      Import a WordPress or Ghost export
+// @Description  Maps the posts, tags, dates and featured images of a WordPress WXR (XML) or Ghost (JSON) export onto content and converts their HTML to Markdown. Nothing is written unless dry_run is false; the report lists what was, or would be, created, updated and skipped. Posts are matched to existing content by language, type and title.
+// @Security     TokenAuth
+// @Tags         content
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        source    path      string  true   "Export format"  Enums(wordpress, ghost)
+// @Param        file      formData  file    true   "Export file"
+// @Param        dry_run   formData  bool    false  "Only report, default true"
+// @Param        language  formData  string  false  "Language when the export names none, default en"
+// @Param        type      formData  string  false  "Content type, default blog"
+// @Param        site_url  formData  string  false  "URL of the old site, to resolve relative links"
+// @Success      200       {object}  importer.Report
+// @Failure      400       {object}  map[string]string  "Invalid export"
+// @Router       /import/{source} [post]
+func importExport(c *gin.Context) {
+	source := c.Param("source")
+	if source != importer.SourceWordPress && source != importer.SourceGhost {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "source must be wordpress or ghost"})
+		return
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "An export file is required"})
+		return
+	}
+
+	dryRun := true
+	if v := c.PostForm("dry_run"); v != "" {
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run must be true or false"})
+			return
+		}
+	}
+	opts := importer.Options{
+		Language: c.PostForm("language"),
+		Type:     c.PostForm("type"),
+		SiteURL:  c.PostForm("site_url"),
+		DryRun:   dryRun,
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not read the export"})
+		return
+	}
+	defer f.Close()
+
+	posts, err := importer.ParseExport(source, f, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var im importer.Importer
+	report := im.ImportPosts(source, posts, opts)
+
+	if !report.DryRun {
+		var created, updated []int64
+		for _, item := range report.Items {
+			switch item.Action {
+			case importer.ActionCreated:
+				created = append(created, item.ID)
+			case importer.ActionUpdated:
+				updated = append(updated, item.ID)
+			}
+		}
+		emitChanged(webhooks.EventContentCreated, created)
+		emitChanged(webhooks.EventContentUpdated, updated)
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,275 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"example.com/portfolio/content"
+	"example.com/portfolio/utils"
+)
+
+const (
+	SourceWordPress = "wordpress"
+	SourceGhost     = "ghost"
+
+	ActionSkipped = "skipped"
+)
+
+// Options control how posts exported from another platform are mapped
+// onto content.
+type Options struct {
+	// Language is used when the export does not name one.
+	Language string
+	Type     string
+	// SiteURL resolves relative links and Ghost's __GHOST_URL__.
+	SiteURL string
+	// DryRun reports what would be imported without writing anything.
+	DryRun bool
+}
+
+func (o *Options) defaults() {
+	if o.Language == "" {
+		o.Language = "en"
+	}
+	if o.Type == "" {
+		o.Type = "blog"
+	}
+	o.SiteURL = strings.TrimRight(o.SiteURL, "/")
+}
+
+// Post is a post read from a WordPress or Ghost export, already converted
+// to Markdown. Posts with a Skip reason are only reported.
+type Post struct {
+	SourceID string
+	Language string
+	Title    string
+	Body     string
+	Tags     []string
+	Featured bool
+	Status   string
+	Image    string
+	Date     time.Time
+	Skip     string
+	Warnings []string
+}
+
+type Report struct {
+	Source  string       `json:"source"`
+	DryRun  bool         `json:"dry_run"`
+	Created int          `json:"created"`
+	Updated int          `json:"updated"`
+	Skipped int          `json:"skipped"`
+	Failed  int          `json:"failed"`
+	Items   []ReportItem `json:"items"`
+}
+
+type ReportItem struct {
+	SourceID string   `json:"source_id"`
+	Title    string   `json:"title"`
+	Action   string   `json:"action"`
+	ID       int64    `json:"id,omitempty"`
+	Language string   `json:"language,omitempty"`
+	Type     string   `json:"type,omitempty"`
+	Status   string   `json:"status,omitempty"`
+	Tags     string   `json:"tags,omitempty"`
+	Featured bool     `json:"featured"`
+	Date     string   `json:"date,omitempty"`
+	Image    string   `json:"image,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// ParseExport reads a WordPress or Ghost export, at most maxTotalSize
+// bytes of it.
+func ParseExport(source string, r io.Reader, opts Options) ([]Post, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxTotalSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxTotalSize {
+		return nil, fmt.Errorf("export is larger than %d MB", maxTotalSize>>20)
+	}
+
+	switch source {
+	case SourceWordPress:
+		return ParseWordPress(bytes.NewReader(data), opts)
+	case SourceGhost:
+		return ParseGhost(data, opts)
+	}
+	return nil, fmt.Errorf("unknown source %q: must be %s or %s", source, SourceWordPress, SourceGhost)
+}
+
+// ImportPosts creates or updates one content item per post. As with
+// Markdown imports, an item with the same language, type and title is
+// updated, including its creation date. Featured images are copied to
+// Cloudinary so that content does not depend on the old site; if that
+// fails the original URL is kept.
+func (im *Importer) ImportPosts(source string, posts []Post, opts Options) Report {
+	opts.defaults()
+	if im.Rehost == nil {
+		im.Rehost = utils.UploadImage
+	}
+
+	report := Report{Source: source, DryRun: opts.DryRun, Items: []ReportItem{}}
+	for _, p := range posts {
+		item := im.importPost(p, opts)
+		switch item.Action {
+		case ActionCreated:
+			report.Created++
+		case ActionUpdated:
+			report.Updated++
+		case ActionSkipped:
+			report.Skipped++
+		default:
+			report.Failed++
+		}
+		report.Items = append(report.Items, item)
+	}
+	return report
+}
+
+func (im *Importer) importPost(p Post, opts Options) ReportItem {
+	item := ReportItem{
+		SourceID: p.SourceID,
+		Title:    p.Title,
+		Warnings: p.Warnings,
+	}
+	if p.Skip != "" {
+		item.Action = ActionSkipped
+		item.Warnings = append(item.Warnings, p.Skip)
+		return item
+	}
+
+	cnt := content.Content{
+		Language: p.Language,
+		Type:     opts.Type,
+		Title:    p.Title,
+		Body:     p.Body,
+		Featured: fmt.Sprint(p.Featured),
+		Status:   p.Status,
+		Image:    p.Image,
+	}
+	if cnt.Language == "" {
+		cnt.Language = opts.Language
+	}
+	if cnt.Image != "" && !strings.HasPrefix(cnt.Image, "http") {
+		item.Warnings = append(item.Warnings, fmt.Sprintf("featured image %s dropped: set the site URL to resolve it", cnt.Image))
+		cnt.Image = ""
+	}
+	cnt.Tag, item.Warnings = joinTags(p.Tags, item.Warnings)
+	if !p.Date.IsZero() {
+		cnt.CreatedAt = p.Date.UTC().Format(time.DateTime)
+	}
+
+	item.Language, item.Type, item.Status = cnt.Language, cnt.Type, cnt.Status
+	item.Tags, item.Featured, item.Date, item.Image = cnt.Tag, p.Featured, cnt.CreatedAt, cnt.Image
+
+	fail := func(err error) ReportItem {
+		item.Action, item.Error = ActionFailed, err.Error()
+		return item
+	}
+
+	if err := cnt.Validate(); err != nil {
+		return fail(err)
+	}
+	id, err := content.FindByTitle(cnt.Language, cnt.Type, cnt.Title)
+	if err != nil {
+		return fail(err)
+	}
+	item.ID = id
+	item.Action = ActionCreated
+	if id != 0 {
+		item.Action = ActionUpdated
+	}
+	if opts.DryRun {
+		return item
+	}
+
+	if cnt.Image != "" {
+		url, err := im.Rehost(cnt.Image)
+		if err != nil {
+			item.Warnings = append(item.Warnings, fmt.Sprintf("featured image kept at %s: %v", cnt.Image, err))
+		} else {
+			cnt.Image = url
+		}
+		item.Image = cnt.Image
+	}
+
+	if id == 0 {
+		if err := cnt.Add(); err != nil {
+			return fail(err)
+		}
+		item.ID = cnt.ID
+		return item
+	}
+
+	existing, err := content.GetById(id)
+	if err != nil {
+		return fail(err)
+	}
+	existing.Language, existing.Type, existing.Title, existing.Body = cnt.Language, cnt.Type, cnt.Title, cnt.Body
+	existing.Tag, existing.Featured, existing.Status = cnt.Tag, cnt.Featured, cnt.Status
+	if cnt.Image != "" {
+		existing.Image = cnt.Image
+	}
+	if cnt.CreatedAt != "" {
+		existing.CreatedAt = cnt.CreatedAt
+	}
+	if err := existing.Update(); err != nil {
+		return fail(err)
+	}
+	return item
+}
+
+// joinTags builds meta_tag from the tags, dropping the ones that do not
+// fit into its 500 characters.
+func joinTags(tags []string, warnings []string) (string, []string) {
+	seen := map[string]bool{}
+	var kept []string
+	length := 0
+	for _, tag := range tags {
+		tag = strings.TrimSpace(strings.ReplaceAll(tag, ",", " "))
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		n := len([]rune(tag))
+		if len(kept) > 0 {
+			n += 2
+		}
+		if length+n > 500 {
+			warnings = append(warnings, fmt.Sprintf("tag %q dropped: meta_tag is limited to 500 characters", tag))
+			continue
+		}
+		kept = append(kept, tag)
+		length += n
+	}
+	return strings.Join(kept, ", "), warnings
+}
+
+// resolveURL makes a link from the export absolute.
+func resolveURL(url, siteURL string) string {
+	switch {
+	case url == "" || strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://"):
+		return url
+	case strings.HasPrefix(url, "//"):
+		return "https:" + url
+	case siteURL != "" && strings.HasPrefix(url, "/"):
+		return siteURL + url
+	}
+	return url
+}
+
+// statusFor maps the publishing state of another platform onto ours;
+// anything not public becomes a draft.
+func statusFor(published bool) string {
+	if published {
+		return content.StatusPublished
+	}
+	return content.StatusDraft
+}
@@ -0,0 +1,191 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	maxFileSize   = 20 << 20
+	maxTotalSize  = 200 << 20
+	maxZipEntries = 2000
+)
+
+// Files is the set of Markdown documents and images of an import, keyed by
+// slash-separated paths relative to the import root. Uploaded files and
+// zip archives are held in memory; imports from disk read the documents
+// up front and the images they reference on demand.
+type Files struct {
+	data  map[string][]byte
+	size  int
+	roots []string
+}
+
+func NewFiles() *Files {
+	return &Files{data: map[string][]byte{}}
+}
+
+// Add stores a file. Zip archives are unpacked into the set, with their
+// entries placed under the archive's directory.
+func (f *Files) Add(name string, data []byte) error {
+	name = cleanPath(name)
+	if strings.EqualFold(path.Ext(name), ".zip") {
+		return f.addZip(path.Dir(name), data)
+	}
+	if len(data) > maxFileSize {
+		return fmt.Errorf("%s is larger than %d MB", name, maxFileSize>>20)
+	}
+	if f.size+len(data) > maxTotalSize {
+		return fmt.Errorf("import is larger than %d MB", maxTotalSize>>20)
+	}
+	f.data[name] = data
+	f.size += len(data)
+	return nil
+}
+
+func (f *Files) addZip(dir string, data []byte) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("invalid zip archive: %w", err)
+	}
+	if len(zr.File) > maxZipEntries {
+		return fmt.Errorf("zip archive has more than %d entries", maxZipEntries)
+	}
+
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() || !wanted(zf.Name) {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return fmt.Errorf("could not read %s: %w", zf.Name, err)
+		}
+		// The declared size is not trusted; one byte more than the limit
+		// is read so that oversized entries are still rejected by Add.
+		content, err := io.ReadAll(io.LimitReader(rc, maxFileSize+1))
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("could not read %s: %w", zf.Name, err)
+		}
+		if err := f.Add(path.Join(dir, zf.Name), content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load collects Markdown files, directories of Markdown files and zip
+// archives from disk. Images are looked up relative to the directory of
+// each argument when a document references them.
+func Load(paths []string) (*Files, error) {
+	f := NewFiles()
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return nil, err
+			}
+			if err := f.Add(filepath.Base(p), data); err != nil {
+				return nil, err
+			}
+			f.roots = append(f.roots, filepath.Dir(p))
+			continue
+		}
+
+		f.roots = append(f.roots, p)
+		err = filepath.WalkDir(p, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !isMarkdown(name) || !wanted(name) {
+				return nil
+			}
+			rel, err := filepath.Rel(p, name)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(name)
+			if err != nil {
+				return err
+			}
+			return f.Add(filepath.ToSlash(rel), data)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// Read returns the file with the given import path.
+func (f *Files) Read(name string) ([]byte, bool) {
+	name = cleanPath(name)
+	if data, ok := f.data[name]; ok {
+		return data, true
+	}
+	for _, root := range f.roots {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		info, err := os.Stat(p)
+		if err != nil || info.IsDir() || info.Size() > maxFileSize {
+			continue
+		}
+		if data, err := os.ReadFile(p); err == nil {
+			return data, true
+		}
+	}
+	return nil, false
+}
+
+// Documents returns the paths of the Markdown documents, sorted.
+func (f *Files) Documents() []string {
+	var docs []string
+	for name := range f.data {
+		if isMarkdown(name) {
+			docs = append(docs, name)
+		}
+	}
+	sort.Strings(docs)
+	return docs
+}
+
+var imageExts = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true,
+	".webp": true, ".svg": true, ".avif": true,
+}
+
+func isMarkdown(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".md" || ext == ".markdown"
+}
+
+func isImage(name string) bool {
+	return imageExts[strings.ToLower(path.Ext(name))]
+}
+
+// wanted skips hidden files and macOS archive metadata.
+func wanted(name string) bool {
+	name = filepath.ToSlash(name)
+	if strings.HasPrefix(path.Base(name), ".") || strings.Contains(name, "__MACOSX/") {
+		return false
+	}
+	return isMarkdown(name) || isImage(name) || strings.EqualFold(path.Ext(name), ".zip")
+}
+
+// cleanPath normalizes a path and keeps it inside the import root.
+func cleanPath(name string) string {
+	name = path.Clean("/" + strings.ReplaceAll(name, `\`, "/"))
+	return strings.TrimPrefix(name, "/")
+}
@@ -0,0 +1,200 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ghostData is the subset of a Ghost JSON export that is imported. Exports
+// wrap it in {"db": [{"data": ...}]}; older ones use {"data": ...}.
+type ghostData struct {
+	Posts []struct {
+		ID           ghostID     `json:"id"`
+		Title        string      `json:"title"`
+		HTML         string      `json:"html"`
+		Plaintext    string      `json:"plaintext"`
+		FeatureImage string      `json:"feature_image"`
+		Featured     interface{} `json:"featured"`
+		Type         string      `json:"type"`
+		Page         interface{} `json:"page"`
+		Status       string      `json:"status"`
+		PublishedAt  ghostTime   `json:"published_at"`
+		CreatedAt    ghostTime   `json:"created_at"`
+	} `json:"posts"`
+	Tags []struct {
+		ID         ghostID `json:"id"`
+		Name       string  `json:"name"`
+		Visibility string  `json:"visibility"`
+	} `json:"tags"`
+	PostsTags []struct {
+		PostID    ghostID `json:"post_id"`
+		TagID     ghostID `json:"tag_id"`
+		SortOrder int     `json:"sort_order"`
+	} `json:"posts_tags"`
+	Settings []struct {
+		Key   string          `json:"key"`
+		Value json.RawMessage `json:"value"`
+	} `json:"settings"`
+}
+
+// ghostID accepts the numeric IDs of Ghost 0.x as well as object IDs.
+type ghostID string
+
+func (id *ghostID) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*id = ""
+		return nil
+	}
+	*id = ghostID(strings.Trim(string(data), `"`))
+	return nil
+}
+
+// ghostTime accepts ISO 8601 strings, millisecond timestamps and null.
+type ghostTime struct{ time.Time }
+
+func (t *ghostTime) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var ms int64
+	if err := json.Unmarshal(data, &ms); err == nil {
+		t.Time = time.UnixMilli(ms).UTC()
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return fmt.Errorf("invalid date %q", s)
+	}
+	t.Time = parsed.UTC()
+	return nil
+}
+
+// truthy reads the booleans that Ghost 0.x stored as 0 and 1.
+func truthy(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	}
+	return false
+}
+
+const ghostURL = "__GHOST_URL__"
+
+// ParseGhost reads the posts of a Ghost JSON export. Pages are skipped
+// and internal tags (#name) are dropped.
+func ParseGhost(data []byte, opts Options) ([]Post, error) {
+	opts.defaults()
+
+	var wrapped struct {
+		DB []struct {
+			Data *ghostData `json:"data"`
+		} `json:"db"`
+		Data *ghostData `json:"data"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, fmt.Errorf("invalid Ghost export: %w", err)
+	}
+	export := wrapped.Data
+	if len(wrapped.DB) > 0 {
+		export = wrapped.DB[0].Data
+	}
+	if export == nil {
+		return nil, errors.New("invalid Ghost export: no data found")
+	}
+
+	language := opts.Language
+	for _, s := range export.Settings {
+		var value string
+		if s.Key == "locale" && json.Unmarshal(s.Value, &value) == nil {
+			if lang := exportLanguage(value); lang != "" {
+				language = lang
+			}
+		}
+	}
+
+	tagNames := map[ghostID]string{}
+	for _, t := range export.Tags {
+		if t.Visibility != "internal" && !strings.HasPrefix(t.Name, "#") {
+			tagNames[t.ID] = t.Name
+		}
+	}
+	postTags := map[ghostID][]string{}
+	sort.SliceStable(export.PostsTags, func(i, j int) bool {
+		return export.PostsTags[i].SortOrder < export.PostsTags[j].SortOrder
+	})
+	for _, pt := range export.PostsTags {
+		if name, ok := tagNames[pt.TagID]; ok {
+			postTags[pt.PostID] = append(postTags[pt.PostID], name)
+		}
+	}
+
+	posts := []Post{}
+	for _, gp := range export.Posts {
+		p := Post{
+			SourceID: string(gp.ID),
+			Language: language,
+			Title:    strings.TrimSpace(gp.Title),
+			Tags:     postTags[gp.ID],
+			Featured: truthy(gp.Featured),
+			Status:   statusFor(gp.Status == "published"),
+			Date:     gp.PublishedAt.Time,
+		}
+		if p.Date.IsZero() {
+			p.Date = gp.CreatedAt.Time
+		}
+
+		switch {
+		case gp.Type == "page" || truthy(gp.Page):
+			p.Skip = "pages are not imported"
+		case p.Title == "":
+			p.Skip = "post has no title"
+		}
+		if p.Skip != "" {
+			posts = append(posts, p)
+			continue
+		}
+
+		html, image := gp.HTML, gp.FeatureImage
+		if strings.Contains(html+image, ghostURL) {
+			if opts.SiteURL == "" {
+				p.Warnings = append(p.Warnings, "links to "+ghostURL+" were left as is; set the site URL to resolve them")
+			} else {
+				html = strings.ReplaceAll(html, ghostURL, opts.SiteURL)
+				image = strings.ReplaceAll(image, ghostURL, opts.SiteURL)
+			}
+		}
+		p.Image = resolveURL(image, opts.SiteURL)
+
+		if strings.TrimSpace(html) == "" {
+			p.Body = strings.TrimSpace(gp.Plaintext)
+			if p.Body != "" {
+				p.Body += "\n"
+				p.Warnings = append(p.Warnings, "post has no HTML; its plain text was imported")
+			}
+		} else {
+			body, err := HTMLToMarkdown(html, opts.SiteURL)
+			if err != nil {
+				p.Skip = err.Error()
+				posts = append(posts, p)
+				continue
+			}
+			p.Body = body
+		}
+		if p.Body == "" {
+			p.Skip = "post has no content"
+		}
+		posts = append(posts, p)
+	}
+	return posts, nil
+}
@@ -0,0 +1,330 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// HTMLToMarkdown converts the HTML body of a post from another platform to
+// Markdown, the format bodies are stored in. Root-relative links and
+// images are resolved against siteURL. Elements Markdown has no syntax
+// for, such as tables and embeds, are kept as HTML.
+func HTMLToMarkdown(s, siteURL string) (string, error) {
+	doc, err := html.Parse(strings.NewReader(s))
+	if err != nil {
+		return "", fmt.Errorf("invalid HTML: %w", err)
+	}
+
+	cv := &converter{siteURL: siteURL}
+	md := cv.expand(tidy(cv.children(doc)))
+	if md == "" {
+		return "", nil
+	}
+	return md + "\n", nil
+}
+
+// converter keeps preformatted output (code blocks, raw HTML) out of the
+// whitespace clean-up by emitting placeholders for it.
+type converter struct {
+	siteURL string
+	raw     []string
+}
+
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.H1: true, atom.H2: true, atom.H3: true,
+	atom.H4: true, atom.H5: true, atom.H6: true, atom.Ul: true, atom.Ol: true,
+	atom.Li: true, atom.Blockquote: true, atom.Pre: true, atom.Hr: true,
+	atom.Table: true, atom.Figure: true, atom.Figcaption: true, atom.Section: true,
+	atom.Article: true, atom.Header: true, atom.Footer: true, atom.Aside: true,
+	atom.Main: true, atom.Nav: true, atom.Iframe: true, atom.Video: true,
+	atom.Audio: true, atom.Html: true, atom.Body: true, atom.Head: true,
+}
+
+func isBlock(n *html.Node) bool {
+	return n != nil && n.Type == html.ElementNode && blockElements[n.DataAtom]
+}
+
+func isElement(n *html.Node, a atom.Atom) bool {
+	return n != nil && n.Type == html.ElementNode && n.DataAtom == a
+}
+
+func (cv *converter) children(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(cv.node(c))
+	}
+	return b.String()
+}
+
+func (cv *converter) node(n *html.Node) string {
+	switch n.Type {
+	case html.DocumentNode:
+		return cv.children(n)
+	case html.TextNode:
+		return text(n)
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Head, atom.Script, atom.Style, atom.Noscript:
+		return ""
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		heading := strings.Join(strings.Fields(cv.children(n)), " ")
+		if heading == "" {
+			return ""
+		}
+		return block(strings.Repeat("#", level) + " " + heading)
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Header, atom.Footer,
+		atom.Aside, atom.Main, atom.Nav, atom.Figure:
+		return block(strings.TrimSpace(cv.children(n)))
+	case atom.Figcaption:
+		caption := strings.TrimSpace(cv.children(n))
+		if caption == "" {
+			return ""
+		}
+		return block("_" + caption + "_")
+	case atom.Br:
+		return "  \n"
+	case atom.Hr:
+		return block("---")
+	case atom.Strong, atom.B:
+		return wrap(cv.children(n), "**")
+	case atom.Em, atom.I:
+		return wrap(cv.children(n), "_")
+	case atom.Del, atom.S, atom.Strike:
+		return wrap(cv.children(n), "~~")
+	case atom.Code:
+		return inlineCode(textContent(n))
+	case atom.A:
+		label := strings.TrimSpace(cv.children(n))
+		href := resolveURL(attr(n, "href"), cv.siteURL)
+		if href == "" {
+			return label
+		}
+		if label == "" {
+			label = escape(href)
+		}
+		return "[" + label + "](" + destination(href) + ")"
+	case atom.Img:
+		src := resolveURL(attr(n, "src"), cv.siteURL)
+		if src == "" {
+			return ""
+		}
+		alt := strings.NewReplacer("[", "", "]", "").Replace(attr(n, "alt"))
+		return "![" + alt + "](" + destination(src) + ")"
+	case atom.Ul, atom.Ol:
+		return block(cv.list(n))
+	case atom.Blockquote:
+		inner := cv.expand(tidy(cv.children(n)))
+		if inner == "" {
+			return ""
+		}
+		lines := strings.Split(inner, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return block(strings.Join(lines, "\n"))
+	case atom.Pre:
+		return block(cv.keep(codeBlock(n)))
+	case atom.Table, atom.Iframe, atom.Video, atom.Audio:
+		var b strings.Builder
+		if err := html.Render(&b, n); err != nil {
+			return ""
+		}
+		return block(cv.keep(b.String()))
+	}
+	return cv.children(n)
+}
+
+func (cv *converter) list(n *html.Node) string {
+	ordered := n.DataAtom == atom.Ol
+	number := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil {
+		number = start
+	}
+
+	var items []string
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.DataAtom != atom.Li {
+			continue
+		}
+
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+
+		item := cv.expand(tidy(cv.children(li)))
+		if !hasChild(li, atom.P) {
+			item = strings.ReplaceAll(item, "\n\n", "\n")
+		}
+		indent := strings.Repeat(" ", len(marker))
+		lines := strings.Split(item, "\n")
+		for i := 1; i < len(lines); i++ {
+			if lines[i] != "" {
+				lines[i] = indent + lines[i]
+			}
+		}
+		items = append(items, marker+strings.Join(lines, "\n"))
+	}
+	return strings.Join(items, "\n")
+}
+
+// keep stores output that must be emitted as is and returns its
+// placeholder. HTML text cannot contain NUL, so the placeholder is unique.
+func (cv *converter) keep(s string) string {
+	cv.raw = append(cv.raw, s)
+	return fmt.Sprintf("\x00%d\x00", len(cv.raw)-1)
+}
+
+var placeholder = regexp.MustCompile("\x00(\\d+)\x00")
+
+func (cv *converter) expand(s string) string {
+	return placeholder.ReplaceAllStringFunc(s, func(m string) string {
+		i, _ := strconv.Atoi(strings.Trim(m, "\x00"))
+		return cv.raw[i]
+	})
+}
+
+var (
+	blankLines = regexp.MustCompile(`\n[ \t]*(\n[ \t]*)+\n`)
+	spaces     = regexp.MustCompile(`[ \t\r\n\f]+`)
+)
+
+// tidy collapses the blank lines left between blocks.
+func tidy(s string) string {
+	s = blankLines.ReplaceAllString(s, "\n\n")
+	return strings.Trim(s, " \n")
+}
+
+func block(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "\n\n" + s + "\n\n"
+}
+
+// text collapses whitespace like a browser does, dropping it next to
+// block boundaries.
+func text(n *html.Node) string {
+	s := spaces.ReplaceAllString(n.Data, " ")
+	if isBlock(n.PrevSibling) || isElement(n.PrevSibling, atom.Br) || (n.PrevSibling == nil && isBlock(n.Parent)) {
+		s = strings.TrimLeft(s, " ")
+	}
+	if isBlock(n.NextSibling) || (n.NextSibling == nil && isBlock(n.Parent)) {
+		s = strings.TrimRight(s, " ")
+	}
+	return escape(s)
+}
+
+var escaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`,
+)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// wrap puts emphasis markers around s, keeping surrounding spaces outside
+// of them as Markdown requires.
+func wrap(s, marker string) string {
+	core := strings.TrimSpace(s)
+	if core == "" {
+		return s
+	}
+	lead := s[:strings.Index(s, core)]
+	trail := s[len(lead)+len(core):]
+	return lead + marker + core + marker + trail
+}
+
+func inlineCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + s + fence
+}
+
+func codeBlock(pre *html.Node) string {
+	code := strings.TrimRight(textContent(pre), "\n")
+	lang := language(pre)
+	for c := pre.FirstChild; c != nil && lang == ""; c = c.NextSibling {
+		if isElement(c, atom.Code) {
+			lang = language(c)
+		}
+	}
+
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + lang + "\n" + code + "\n" + fence
+}
+
+// language reads the language-x or lang-x class used by syntax
+// highlighters.
+func language(n *html.Node) string {
+	for _, class := range strings.Fields(attr(n, "class")) {
+		for _, prefix := range []string{"language-", "lang-"} {
+			if strings.HasPrefix(class, prefix) {
+				return strings.TrimPrefix(class, prefix)
+			}
+		}
+	}
+	return ""
+}
+
+func destination(url string) string {
+	if strings.ContainsAny(url, " ()") {
+		return "<" + url + ">"
+	}
+	return url
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if isElement(c, atom.Br) {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+func hasChild(n *html.Node, a atom.Atom) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if isElement(c, a) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,214 @@
+// Package importer creates and updates content from Markdown files with
+// YAML front matter.
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"example.com/portfolio/content"
+	"example.com/portfolio/utils"
+)
+
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionFailed  = "failed"
+)
+
+type Result struct {
+	File   string `json:"file"`
+	ID     int64  `json:"id,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Importer imports documents, uploading the images they reference with
+// Upload and copying remote images with Rehost. The zero value uses
+// Cloudinary for both.
+type Importer struct {
+	Upload func(name string, data []byte) (string, error)
+	Rehost func(url string) (string, error)
+
+	uploaded map[string]string
+}
+
+// Import creates or updates one content item per Markdown document. An
+// item is updated if the front matter has its id, or if an item with the
+// same language, type and title exists; otherwise it is created. A failed
+// document does not stop the others.
+func (im *Importer) Import(files *Files) []Result {
+	if im.Upload == nil {
+		im.Upload = uploadImage
+	}
+	im.uploaded = map[string]string{}
+
+	results := []Result{}
+	for _, name := range files.Documents() {
+		data, _ := files.Read(name)
+		r := Result{File: name}
+
+		doc, err := ParseMarkdown(name, data)
+		if err == nil {
+			r.Title = doc.Meta.Title
+			r.ID, r.Action, err = im.importDocument(files, doc)
+		}
+		if err != nil {
+			r.Action, r.Error = ActionFailed, err.Error()
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+func (im *Importer) importDocument(files *Files, doc Document) (int64, string, error) {
+	m := doc.Meta
+
+	id := m.ID
+	if id == 0 {
+		var err error
+		id, err = content.FindByTitle(m.Language, m.Type, m.Title)
+		if err != nil {
+			return 0, "", err
+		}
+	}
+
+	var cnt content.Content
+	if id != 0 {
+		var err error
+		cnt, err = content.GetById(id)
+		if err != nil {
+			return 0, "", fmt.Errorf("content %d: %w", id, err)
+		}
+	}
+
+	cnt.Language, cnt.Type, cnt.Title, cnt.Body = m.Language, m.Type, m.Title, doc.Body
+	cnt.Tag = strings.Join(m.Tags, ", ")
+	cnt.Featured = fmt.Sprint(m.Featured)
+	if m.Status != "" {
+		cnt.Status = m.Status
+	}
+	if !m.Date.IsZero() {
+		cnt.CreatedAt = m.Date.UTC().Format(time.DateTime)
+	}
+
+	// Nothing is uploaded for a document that cannot be saved anyway.
+	if err := cnt.Validate(); err != nil {
+		return 0, "", err
+	}
+
+	body, err := im.rewriteImages(files, doc)
+	if err != nil {
+		return 0, "", err
+	}
+	cnt.Body = body
+
+	if m.Cover != "" {
+		cnt.Image, err = im.image(files, doc.Path, m.Cover)
+		if err != nil {
+			return 0, "", fmt.Errorf("cover: %w", err)
+		}
+	}
+
+	if id != 0 {
+		if err := cnt.Update(); err != nil {
+			return 0, "", err
+		}
+		return cnt.ID, ActionUpdated, nil
+	}
+
+	if err := cnt.Add(); err != nil {
+		return 0, "", err
+	}
+	return cnt.ID, ActionCreated, nil
+}
+
+var (
+	markdownImage = regexp.MustCompile(`(!\[[^\]]*\]\(\s*<?)([^)\s>]+)(>?(?:\s+"[^"]*")?\s*\))`)
+	htmlImage     = regexp.MustCompile(`(<img\s[^>]*?src=["'])([^"']+)(["'])`)
+)
+
+// rewriteImages uploads the local images referenced from the body and
+// points the references at the uploaded copies.
+func (im *Importer) rewriteImages(files *Files, doc Document) (string, error) {
+	var firstErr error
+	replace := func(re *regexp.Regexp, s string) string {
+		return re.ReplaceAllStringFunc(s, func(match string) string {
+			parts := re.FindStringSubmatch(match)
+			url, err := im.image(files, doc.Path, parts[2])
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return match
+			}
+			return parts[1] + url + parts[3]
+		})
+	}
+
+	body := replace(markdownImage, doc.Body)
+	body = replace(htmlImage, body)
+	return body, firstErr
+}
+
+// image returns the URL of an image referenced from the document at
+// docPath, uploading it the first time. Remote images are left alone.
+func (im *Importer) image(files *Files, docPath, ref string) (string, error) {
+	if isRemote(ref) {
+		return ref, nil
+	}
+
+	name := path.Join(path.Dir(docPath), ref)
+	if strings.HasPrefix(ref, "/") {
+		name = ref
+	}
+	name = cleanPath(name)
+
+	if url, ok := im.uploaded[name]; ok {
+		return url, nil
+	}
+	if !isImage(name) {
+		return "", fmt.Errorf("%s is not an image", ref)
+	}
+	data, ok := files.Read(name)
+	if !ok {
+		return "", fmt.Errorf("image %s is not part of the import", ref)
+	}
+
+	url, err := im.Upload(name, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload %s: %w", ref, err)
+	}
+	im.uploaded[name] = url
+	return url, nil
+}
+
+func isRemote(ref string) bool {
+	lower := strings.ToLower(ref)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") ||
+		strings.HasPrefix(lower, "data:") || strings.HasPrefix(lower, "//") || strings.HasPrefix(lower, "#")
+}
+
+// uploadImage uploads to Cloudinary through a temporary file, as
+// utils.UploadImage takes a path.
+func uploadImage(name string, data []byte) (string, error) {
+	tmp, err := os.CreateTemp("", "import-*"+path.Ext(name))
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	return utils.UploadImage(tmp.Name())
+}
@@ -0,0 +1,128 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// FrontMatter is the YAML header of an imported Markdown document.
+//
+//	---
+//	id: 12            # optional, updates this content item
+//	language: en
+//	type: blog
+//	title: Hello
+//	tags: [go, backend]  # or "go, backend"
+//	featured: true
+//	status: draft
+//	cover: images/cover.png
+//	date: 2024-05-01
+//	---
+type FrontMatter struct {
+	ID       int64    `yaml:"id"`
+	Language string   `yaml:"language"`
+	Type     string   `yaml:"type"`
+	Title    string   `yaml:"title"`
+	Tags     tagList  `yaml:"tags"`
+	Featured bool     `yaml:"featured"`
+	Status   string   `yaml:"status"`
+	Cover    string   `yaml:"cover"`
+	Date     yamlTime `yaml:"date"`
+}
+
+// Document is a parsed Markdown file.
+type Document struct {
+	Path string
+	Meta FrontMatter
+	Body string
+}
+
+// tagList accepts tags as a YAML list or as a comma-separated string.
+type tagList []string
+
+func (t *tagList) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		*t = nil
+		for _, tag := range strings.Split(value.Value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				*t = append(*t, tag)
+			}
+		}
+		return nil
+	case yaml.SequenceNode:
+		var tags []string
+		if err := value.Decode(&tags); err != nil {
+			return err
+		}
+		*t = tags
+		return nil
+	}
+	return errors.New("tags must be a list or a comma-separated string")
+}
+
+// yamlTime accepts a date, a date and time, or an RFC 3339 timestamp.
+type yamlTime struct{ time.Time }
+
+var dateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+func (t *yamlTime) UnmarshalYAML(value *yaml.Node) error {
+	s := strings.TrimSpace(value.Value)
+	if s == "" {
+		return nil
+	}
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, s); err == nil {
+			t.Time = parsed.UTC()
+			return nil
+		}
+	}
+	return fmt.Errorf("date %q must be YYYY-MM-DD, YYYY-MM-DD HH:MM:SS or RFC 3339", s)
+}
+
+// ParseMarkdown splits a document into its front matter and body. Without
+// a title in the front matter, a leading "# Heading" is used as the title
+// and removed from the body.
+func ParseMarkdown(name string, data []byte) (Document, error) {
+	doc := Document{Path: name}
+
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	if !strings.HasPrefix(text, "---\n") {
+		return doc, errors.New("missing front matter: the file must start with a --- line")
+	}
+	rest := text[len("---\n"):]
+	end := strings.Index(rest, "\n---\n")
+	header, body := "", ""
+	switch {
+	case end >= 0:
+		header, body = rest[:end], rest[end+len("\n---\n"):]
+	case strings.HasSuffix(rest, "\n---"):
+		header = strings.TrimSuffix(rest, "\n---")
+	default:
+		return doc, errors.New("front matter is not closed with a --- line")
+	}
+
+	if err := yaml.Unmarshal([]byte(header), &doc.Meta); err != nil {
+		return doc, fmt.Errorf("invalid front matter: %w", err)
+	}
+
+	body = strings.TrimLeft(body, "\n")
+	if doc.Meta.Title == "" && strings.HasPrefix(body, "# ") {
+		line, remainder, _ := strings.Cut(body, "\n")
+		doc.Meta.Title = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+		body = strings.TrimLeft(remainder, "\n")
+	}
+	doc.Body = strings.TrimRight(body, "\n") + "\n"
+
+	doc.Meta.Language = strings.ToLower(strings.TrimSpace(doc.Meta.Language))
+	doc.Meta.Type = strings.ToLower(strings.TrimSpace(doc.Meta.Type))
+	doc.Meta.Title = strings.TrimSpace(doc.Meta.Title)
+	return doc, nil
+}
@@ -0,0 +1,205 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"example.com/portfolio/content"
+)
+
+// wxr is the subset of a WordPress eXtended RSS export that is imported.
+// Elements without a namespace match any WXR version.
+type wxr struct {
+	Channel struct {
+		Language string    `xml:"language"`
+		BaseURL  string    `xml:"base_blog_url"`
+		Items    []wxrItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type wxrItem struct {
+	Title      string        `xml:"title"`
+	Content    string        `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PostID     string        `xml:"post_id"`
+	Date       string        `xml:"post_date"`
+	DateGMT    string        `xml:"post_date_gmt"`
+	Type       string        `xml:"post_type"`
+	Status     string        `xml:"status"`
+	Sticky     string        `xml:"is_sticky"`
+	Attachment string        `xml:"attachment_url"`
+	Categories []wxrCategory `xml:"category"`
+	Meta       []struct {
+		Key   string `xml:"meta_key"`
+		Value string `xml:"meta_value"`
+	} `xml:"postmeta"`
+}
+
+type wxrCategory struct {
+	Domain   string `xml:"domain,attr"`
+	Nicename string `xml:"nicename,attr"`
+	Name     string `xml:",chardata"`
+}
+
+// ParseWordPress reads the posts of a WordPress WXR export. Pages,
+// attachments and other post types are skipped; the featured image is
+// taken from the attachment that _thumbnail_id points to.
+func ParseWordPress(r io.Reader, opts Options) ([]Post, error) {
+	opts.defaults()
+
+	var export wxr
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	if err := dec.Decode(&export); err != nil {
+		return nil, fmt.Errorf("invalid WXR export: %w", err)
+	}
+	if opts.SiteURL == "" {
+		opts.SiteURL = strings.TrimRight(export.Channel.BaseURL, "/")
+	}
+
+	language := opts.Language
+	if lang := exportLanguage(export.Channel.Language); lang != "" {
+		language = lang
+	}
+
+	attachments := map[string]string{}
+	for _, item := range export.Channel.Items {
+		if item.Type == "attachment" && item.Attachment != "" {
+			attachments[item.PostID] = item.Attachment
+		}
+	}
+
+	posts := []Post{}
+	for _, item := range export.Channel.Items {
+		if item.Type == "attachment" || item.Type == "nav_menu_item" {
+			continue
+		}
+		posts = append(posts, wordPressPost(item, attachments, language, opts))
+	}
+	return posts, nil
+}
+
+func wordPressPost(item wxrItem, attachments map[string]string, language string, opts Options) Post {
+	p := Post{
+		SourceID: item.PostID,
+		Language: language,
+		Title:    strings.TrimSpace(item.Title),
+		Featured: strings.TrimSpace(item.Sticky) == "1",
+		Status:   statusFor(item.Status == "publish"),
+	}
+
+	switch {
+	case item.Type != "post":
+		p.Skip = fmt.Sprintf("post type %s is not imported", item.Type)
+		return p
+	case item.Status == "trash" || item.Status == "auto-draft" || item.Status == "inherit":
+		p.Skip = fmt.Sprintf("status %s is not imported", item.Status)
+		return p
+	case p.Title == "":
+		p.Skip = "post has no title"
+		return p
+	}
+
+	p.Date = wordPressDate(item.DateGMT, time.UTC)
+	if p.Date.IsZero() {
+		p.Date = wordPressDate(item.Date, time.Local)
+	}
+
+	for _, c := range item.Categories {
+		// Posts without a category are filed under "Uncategorized", which
+		// says nothing about them.
+		if c.Domain == "post_tag" || (c.Domain == "category" && c.Nicename != "uncategorized") {
+			p.Tags = append(p.Tags, strings.TrimSpace(c.Name))
+		}
+	}
+
+	for _, m := range item.Meta {
+		if m.Key != "_thumbnail_id" {
+			continue
+		}
+		if url, ok := attachments[strings.TrimSpace(m.Value)]; ok {
+			p.Image = resolveURL(url, opts.SiteURL)
+		} else {
+			p.Warnings = append(p.Warnings, fmt.Sprintf("featured image %s is not in the export", m.Value))
+		}
+	}
+
+	body, err := HTMLToMarkdown(autop(captions(item.Content)), opts.SiteURL)
+	if err != nil {
+		p.Skip = err.Error()
+		return p
+	}
+	if shortcode.MatchString(body) {
+		p.Warnings = append(p.Warnings, "body contains WordPress shortcodes that were left as text")
+	}
+	p.Body = body
+	if strings.TrimSpace(p.Body) == "" {
+		p.Skip = "post has no content"
+	}
+	return p
+}
+
+// wordPressDate parses a WXR date; drafts carry a zero date.
+func wordPressDate(s string, loc *time.Location) time.Time {
+	t, err := time.ParseInLocation(time.DateTime, strings.TrimSpace(s), loc)
+	if err != nil || t.Year() < 1970 {
+		return time.Time{}
+	}
+	return t
+}
+
+// exportLanguage maps a locale such as en-US onto a content language.
+func exportLanguage(locale string) string {
+	lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(locale)), "-")
+	lang, _, _ = strings.Cut(lang, "_")
+	for _, l := range content.Languages {
+		if l == lang {
+			return l
+		}
+	}
+	return ""
+}
+
+var (
+	captionShortcode = regexp.MustCompile(`(?s)\[caption[^\]]*\](.*?)\[/caption\]`)
+	shortcode        = regexp.MustCompile(`\\\[/?(gallery|embed|video|audio|playlist|caption|wp_[a-z_]+)\b`)
+	paragraphBreak   = regexp.MustCompile(`\n\s*\n`)
+	blockStart       = regexp.MustCompile(`(?i)^<(/?(p|div|h[1-6]|ul|ol|li|blockquote|pre|table|figure|hr|iframe)\b|!--)`)
+)
+
+// captions turns [caption]<img> Text[/caption] into a figure.
+func captions(s string) string {
+	return captionShortcode.ReplaceAllStringFunc(s, func(m string) string {
+		inner := captionShortcode.FindStringSubmatch(m)[1]
+		i := strings.LastIndex(inner, ">")
+		if i < 0 {
+			return inner
+		}
+		return "<figure>" + inner[:i+1] + "<figcaption>" + strings.TrimSpace(inner[i+1:]) + "</figcaption></figure>"
+	})
+}
+
+// autop adds the paragraphs that the classic WordPress editor leaves to
+// the theme: blank lines separate paragraphs and single newlines are line
+// breaks. Content from the block editor already has them.
+func autop(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	lower := strings.ToLower(s)
+	if strings.Contains(lower, "<p>") || strings.Contains(lower, "<p ") || strings.Contains(lower, "<pre") {
+		return s
+	}
+
+	chunks := paragraphBreak.Split(strings.TrimSpace(s), -1)
+	for i, chunk := range chunks {
+		chunk = strings.TrimSpace(chunk)
+		if blockStart.MatchString(chunk) {
+			chunks[i] = chunk
+			continue
+		}
+		chunks[i] = "<p>" + strings.ReplaceAll(chunk, "\n", "<br>\n") + "</p>"
+	}
+	return strings.Join(chunks, "\n\n")
+}
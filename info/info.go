@@ -1,27 +1,57 @@
 package info
 
 import (
-	"errors"
+	"regexp"
 	"strings"
 	"time"
 
 	"example.com/portfolio/db"
+	"example.com/portfolio/validation"
 )
 
 type About struct {
 	ID          int64     `json:"id"`
-	Name        string    `json:"name" binding:"required"`
-	Lastname    string    `json:"lastname" binding:"required"`
-	Phone       string    `json:"phone" binding:"required"`
+	Name        string    `json:"name"`
+	Lastname    string    `json:"lastname"`
+	Phone       string    `json:"phone"`
 	Description string    `json:"description"`
-	Telegram    string    `json:"telegram" binding:"required"`
+	Telegram    string    `json:"telegram"`
 	IP          string    `json:"ip"`
 	CreatedAt   time.Time `json:"createdAt"`
 }
 
+var (
+	phonePattern    = regexp.MustCompile(`^\+?[0-9][0-9 ()-]{5,18}[0-9]$`)
+	telegramPattern = regexp.MustCompile(`^@[A-Za-z][A-Za-z0-9_]{4,31}$`)
+)
+
+// Validate reports every field of the request that cannot be accepted.
+func (a *About) Validate() error {
+	a.Name = strings.TrimSpace(a.Name)
+	a.Lastname = strings.TrimSpace(a.Lastname)
+	a.Phone = strings.TrimSpace(a.Phone)
+	a.Telegram = strings.TrimSpace(a.Telegram)
+
+	var errs validation.Errors
+	if errs.Required("name", a.Name) {
+		errs.MaxLength("name", a.Name, 50)
+	}
+	if errs.Required("lastname", a.Lastname) {
+		errs.MaxLength("lastname", a.Lastname, 50)
+	}
+	if errs.Required("phone", a.Phone) {
+		errs.Match("phone", a.Phone, phonePattern, "must be a phone number such as +998 90 123 45 67")
+	}
+	errs.MaxLength("description", a.Description, 1000)
+	if errs.Required("telegram", a.Telegram) {
+		errs.Match("telegram", a.Telegram, telegramPattern, "must be a Telegram username starting with '@'")
+	}
+	return errs.Err()
+}
+
 func (a *About) Save(ip string) error {
-	if !strings.HasPrefix(a.Telegram, "@") {
-		return errors.New("the telegram username should start with '@'")
+	if err := a.Validate(); err != nil {
+		return err
 	}
 
 	query := `
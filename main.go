@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -9,15 +11,21 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	_ "example.com/portfolio/docs"
 
 	"example.com/portfolio/admin"
+	"example.com/portfolio/analytics"
 	"example.com/portfolio/content"
 	"example.com/portfolio/db"
 	"example.com/portfolio/info"
 	"example.com/portfolio/middlewares"
+	"example.com/portfolio/newsletter"
 	"example.com/portfolio/utils"
+	"example.com/portfolio/validation"
+	"example.com/portfolio/webhooks"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -28,6 +36,10 @@ import (
 var (
 	botToken string
 	adminID  string
+
+	// started is the Last-Modified time of responses that only change
+	// with a new deployment.
+	started = time.Now()
 )
 
 func init() {
@@ -66,11 +78,18 @@ func init() {
 
 func main() {
 	db.Initdb()
+	webhooks.Start(context.Background())
+	newsletter.ConfigureMailer()
+	if err := content.SyncSearchIndex(); err != nil {
+		log.Printf("⚠️ Could not sync blog_search_stem with blog_data: %v", err)
+	}
+	content.ConfigureCache()
 	r := gin.Default()
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, If-Match, If-None-Match, If-Modified-Since")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag, Last-Modified")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -82,14 +101,58 @@ func main() {
 	{
 		auth.POST("/post", publishBlog)
 		auth.PUT("/update/:id", editBlog)
+		auth.PATCH("/update/:id", patchBlog)
 		auth.DELETE("/delete/:id", deleteBlog)
+		auth.POST("/bulk", bulk)
+		auth.POST("/import", importContent)
+		auth.POST("/import/:source", importExport)
+		auth.GET("/search/report", searchReport)
+		auth.GET("/comments", moderationQueue)
+		auth.PUT("/comments/:id/status", moderateComment)
+		auth.DELETE("/comments/:id", deleteComment)
+		auth.POST("/series", createSeries)
+		auth.PUT("/series/:id", editSeries)
+		auth.DELETE("/series/:id", deleteSeries)
+		auth.PUT("/series/:id/items", setSeriesItems)
+		auth.POST("/series/:id/items", addSeriesItem)
+		auth.DELETE("/series/:id/items/:content", removeSeriesItem)
+		auth.POST("/blog/:id/gallery", uploadGallery)
+		auth.PUT("/blog/:id/gallery", reorderGallery)
+		auth.PUT("/blog/:id/gallery/:image", editGalleryImage)
+		auth.DELETE("/blog/:id/gallery/:image", deleteGalleryImage)
+		auth.GET("/cache/stats", cacheStats)
+		auth.POST("/cache/purge", purgeCache)
+		auth.PUT("/cache/enabled", switchCache)
+		auth.POST("/webhooks", createWebhook)
+		auth.GET("/webhooks", listWebhooks)
+		auth.PUT("/webhooks/:id", editWebhook)
+		auth.DELETE("/webhooks/:id", deleteWebhook)
+		auth.POST("/webhooks/:id/ping", pingWebhook)
+		auth.GET("/webhooks/:id/deliveries", listDeliveries)
+		auth.POST("/webhooks/:id/deliveries/:delivery/redeliver", redeliver)
+		auth.GET("/newsletter/subscribers", listSubscribers)
 	}
 	fmt.Println("SHOW_SIGNUP =", os.Getenv("SHOW_SIGNUP"))
-	r.GET("/blog/:id", getSingle)
-	r.GET("/portfolio", hello)
+	r.GET("/blog/:id", middlewares.CacheControl("CACHE_CONTROL_BLOG", "public, max-age=60, stale-while-revalidate=300"), getSingle)
+	r.GET("/blog/:id/related", related)
+	r.GET("/blog/:id/comments", listComments)
+	r.POST("/blog/:id/comments", postComment)
+	r.POST("/blog/:id/reactions", react)
+	r.DELETE("/blog/:id/reactions/:reaction", unreact)
+	r.GET("/series", listSeries)
+	r.GET("/series/:id", getSeries)
+	r.GET("/portfolio", middlewares.CacheControl("CACHE_CONTROL_PORTFOLIO", "public, max-age=3600"), hello)
 	r.GET("/health", health)
-	r.GET("/blogs/:page", blogs)
+	r.GET("/export", middlewares.CacheControl("CACHE_CONTROL_EXPORT", "public, max-age=300"), exportContent)
+	r.GET("/blogs/:page", middlewares.CacheControl("CACHE_CONTROL_BLOGS", "public, max-age=30, stale-while-revalidate=120"), blogs)
+	r.GET("/archive", middlewares.CacheControl("CACHE_CONTROL_ARCHIVE", "public, max-age=300"), archive)
+	r.GET("/suggest", suggest)
+	r.GET("/search", search)
 	r.POST("/request", request)
+	r.POST("/newsletter/subscribe", subscribe)
+	r.GET("/newsletter/confirm", confirmSubscription)
+	r.GET("/newsletter/unsubscribe", unsubscribePage)
+	r.POST("/newsletter/unsubscribe", unsubscribe)
 	showSignup := os.Getenv("SHOW_SIGNUP")
 	if showSignup == "true" {
 		r.POST("/signup", register)
@@ -114,8 +177,12 @@ func main() {
 // @Tags         general
 // @Produce      json
 // @Success      200  {object}  map[string]string
+// @Success      304  "Not modified"
 // @Router       /portfolio [get]
 func hello(c *gin.Context) {
+	if middlewares.NotModified(c, `"hello-world"`, started) {
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{"message": "Hello world"})
 }
 
@@ -132,15 +199,19 @@ func health(c *gin.Context) {
 // @Produce      json
 // @Param        request  body      info.About  true  "Portfolio request data"
 // @Success      200      {object}  map[string]interface{}  "Request successfully submitted"
-// @Failure      400      {object}  map[string]string        "Invalid input"
+// @Failure      400      {object}  map[string]string        "Invalid request body"
+// @Failure      422      {object}  map[string]interface{}   "Validation failed; lists each failing field"
 // @Failure      429      {object}  map[string]string        "Daily request limit reached"
 // @Failure      500      {object}  map[string]string        "Server or database error"
 // @Router       /request [post]
 func request(c *gin.Context) {
 	var i info.About
 
-	if err := c.ShouldBindJSON(&i); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &i) {
+		return
+	}
+	if err := i.Validate(); err != nil {
+		invalidInput(c, err)
 		return
 	}
 
@@ -168,6 +239,8 @@ func request(c *gin.Context) {
 		i.Name, i.Lastname, i.Phone, i.Telegram, i.Description, ip,
 	)
 
+	webhooks.Emit(webhooks.EventRequestCreated, createdRequest{ID: i.ID, Name: i.Name, CreatedAt: i.CreatedAt})
+
 	if err := sendTelegramMessage(botToken, adminID, msg); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send Telegram notification"})
 		return
@@ -208,21 +281,64 @@ func sendTelegramMessage(token, chatID, message string) error {
 // @Param        title     formData  string  true  "Title"
 // @Param        body      formData  string  true  "Body"
 // @Param        meta_tag  formData  string  false "Meta tags"
+// @Param        status    formData  string  false "Publication status (default published)"  Enums(published, draft, archived)
+// @Param        translation_group  formData  int  false "ID of an item this is a translation of"
+// @Param        repo_url    formData  string  false "Project repository URL (type=project)"
+// @Param        demo_url    formData  string  false "Project live demo URL (type=project)"
+// @Param        tech_stack  formData  string  false "Comma-separated technologies (type=project)"
+// @Param        role        formData  string  false "Role in the project (type=project)"
+// @Param        client      formData  string  false "Client (type=project)"
+// @Param        start_date  formData  string  false "Start date, YYYY-MM or YYYY-MM-DD (type=project)"
+// @Param        end_date    formData  string  false "End date, YYYY-MM or YYYY-MM-DD (type=project)"
 // @Success      201  {object}  content.Content
 // @Failure      400  {object}  map[string]string
+// @Failure      422  {object}  map[string]interface{}  "Validation failed; lists each failing field"
 // @Failure      500  {object}  map[string]string
 // @Router       /post [post]
 func publishBlog(c *gin.Context) {
+	k := content.Content{
+		Language: c.PostForm("language"),
+		Type:     c.PostForm("type"),
+		Title:    c.PostForm("title"),
+		Body:     c.PostForm("body"),
+		Tag:      c.PostForm("meta_tag"),
+		Status:   c.PostForm("status"),
+	}
 
-	language := c.PostForm("language")
-	typ := c.PostForm("type")
-	title := c.PostForm("title")
-	body := c.PostForm("body")
-	metaTag := c.PostForm("meta_tag")
+	p := content.Project{
+		RepoURL:   c.PostForm("repo_url"),
+		DemoURL:   c.PostForm("demo_url"),
+		TechStack: content.ParseTechStack(c.PostForm("tech_stack")),
+		Role:      c.PostForm("role"),
+		Client:    c.PostForm("client"),
+		StartDate: c.PostForm("start_date"),
+		EndDate:   c.PostForm("end_date"),
+	}
+	if p.RepoURL != "" || p.DemoURL != "" || len(p.TechStack) > 0 || p.Role != "" ||
+		p.Client != "" || p.StartDate != "" || p.EndDate != "" {
+		k.Project = &p
+	}
 
+	// Check the fields before the upload so that every problem, including
+	// a missing image, is reported at once.
+	var errs validation.Errors
+	if err := k.Validate(); err != nil && !errors.As(err, &errs) {
+		invalidInput(c, err)
+		return
+	}
+	if group := c.PostForm("translation_group"); group != "" {
+		id, err := strconv.ParseInt(group, 10, 64)
+		if err != nil || id < 1 {
+			errs.Add("translation_group", "must be a content ID")
+		}
+		k.TranslationGroup = id
+	}
 	file, err := c.FormFile("image")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Image file is required"})
+		errs.Add("image", "is required")
+	}
+	if len(errs) > 0 {
+		invalidInput(c, errs)
 		return
 	}
 
@@ -231,20 +347,22 @@ func publishBlog(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save image"})
 		return
 	}
-
-	k := content.Content{
-		Language: language,
-		Type:     typ,
-		Title:    title,
-		Body:     body,
-		Image:    filename,
-		Tag:      metaTag,
+	k.Image, err = utils.UploadImage(filename)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not publish the blog", "details": "failed to upload image: " + err.Error()})
+		return
 	}
 
 	if err := k.Add(); err != nil {
+		if errors.Is(err, content.ErrInvalid) {
+			invalidInput(c, err)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not publish the blog", "details": err.Error()})
 		return
 	}
+	webhooks.Emit(webhooks.EventContentCreated, k)
+	notifyPublished("", k)
 
 	c.JSON(http.StatusCreated, k)
 }
@@ -257,11 +375,15 @@ func publishBlog(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID number to fetch"
+// @Param If-Match header string false "ETag of the revision being edited"
 // @Param content body content.Content true "Blog data"
 // @Success 200 {object} map[string]string  "Blog updated successfully"
 // @Failure 400 {object} map[string]string "Invalid blog ID"
 // @Failure 400 {object} map[string]string  "Could not find blog with this ID"
-// @Failure 500 {object} map[string]string "Invalid request body"
+// @Failure 400 {object} map[string]string "Invalid request body"
+// @Failure 412 {object} map[string]interface{} "Content has been modified; returns the current version"
+// @Failure 422 {object} map[string]interface{} "Validation failed; lists each failing field"
+// @Failure 500 {object} map[string]string "Failed to update blog"
 // @Router /update/{id} [put]
 func editBlog(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -275,21 +397,112 @@ func editBlog(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Could not find blog with this ID"})
 		return
 	}
+	if !ifMatch(c, cnt) {
+		return
+	}
+	before := cnt.Status
 
-	if err := c.ShouldBindJSON(&cnt); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if !bindJSON(c, &cnt) {
 		return
 	}
 
 	cnt.ID = id
 
 	if err := cnt.Update(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update blog"})
+		switch {
+		case errors.Is(err, content.ErrVersionConflict):
+			versionConflict(c, id)
+		case errors.Is(err, content.ErrInvalid):
+			invalidInput(c, err)
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update blog"})
+		}
+		return
+	}
+
+	webhooks.Emit(webhooks.EventContentUpdated, cnt)
+	notifyPublished(before, cnt)
+
+	c.Header("ETag", cnt.ETag())
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Blog updated successfully",
+		"content": cnt,
+	})
+}
+
+// patchBlog godoc
+// @Summary      Partially update content
+// @Description  Applies a JSON merge patch (RFC 7396) to a content item. Only the fields present in the patch change; null removes an optional field. The image can only be set to an uploaded URL. Returns the list of changed fields.
+// @Security     TokenAuth
+// @Tags         content
+// @Accept       application/merge-patch+json
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int              true   "Blog ID"
+// @Param        If-Match  header    string           false  "ETag of the revision being edited"
+// @Param        patch     body      content.Content  true   "Merge patch"
+// @Success      200    {object}  map[string]interface{}  "Changed fields and the updated content"
+// @Failure      400    {object}  map[string]string       "Invalid patch"
+// @Failure      404    {object}  map[string]string       "Blog not found"
+// @Failure      412    {object}  map[string]interface{}  "Content has been modified; returns the current version"
+// @Failure      415    {object}  map[string]string       "Unsupported content type"
+// @Failure      422    {object}  map[string]interface{}  "Validation failed; lists each failing field"
+// @Failure      500    {object}  map[string]string       "Failed to update blog"
+// @Router       /update/{id} [patch]
+func patchBlog(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blog ID"})
+		return
+	}
+
+	if ct := c.ContentType(); ct != "application/merge-patch+json" && ct != "application/json" {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be application/merge-patch+json"})
+		return
+	}
+
+	cnt, err := content.GetById(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Could not find blog with this ID"})
+		return
+	}
+	if !ifMatch(c, cnt) {
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
 
+	before := cnt.Status
+	changed, err := cnt.ApplyMergePatch(patch)
+	if err != nil {
+		invalidInput(c, err)
+		return
+	}
+
+	if len(changed) > 0 {
+		if err := cnt.Update(); err != nil {
+			switch {
+			case errors.Is(err, content.ErrVersionConflict):
+				versionConflict(c, id)
+			case errors.Is(err, content.ErrInvalid):
+				invalidInput(c, err)
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update blog"})
+			}
+			return
+		}
+		webhooks.Emit(webhooks.EventContentUpdated, cnt)
+		notifyPublished(before, cnt)
+	}
+
+	c.Header("ETag", cnt.ETag())
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Blog updated successfully",
+		"changed": changed,
 		"content": cnt,
 	})
 }
@@ -302,9 +515,11 @@ func editBlog(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID number to fetch"
+// @Param If-Match header string false "ETag of the revision being deleted"
 // @Success 200 {object} map[string]string  "Blog deleted successfully"
 // @Failure 400 {object} map[string]string "Invalid blog ID"
 // @Failure 400 {object} map[string]string  "Could not find blog with this ID"
+// @Failure 412 {object} map[string]interface{} "Content has been modified; returns the current version"
 // @Failure 500 {object} map[string]string "Failed to delete blog"
 // @Router /delete/{id} [delete]
 func deleteBlog(c *gin.Context) {
@@ -320,27 +535,48 @@ func deleteBlog(c *gin.Context) {
 		return
 	}
 
+	if !ifMatch(c, cnt) {
+		return
+	}
+
 	cnt.ID = id
 	if err := cnt.Delete(); err != nil {
+		if errors.Is(err, content.ErrVersionConflict) {
+			versionConflict(c, id)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete blog"})
 		return
 	}
+	webhooks.Emit(webhooks.EventContentDeleted, deletedContent{ID: id})
 
 	c.JSON(http.StatusOK, gin.H{"message": "Blog deleted successfully"})
 }
 
 // blogs godoc
 // @Summary      Get blogs
-// @Description  Returns paginated blogs with optional filters for language, category, and title.
+// @Description  Returns paginated blogs with optional filters for language, category, and title. Without a title, items not translated into the language are listed in the first available language of the LANGUAGE_FALLBACK chain (default uz→ru→en, ru→en) and carry requested_language.
 // @Tags         Content
 // @Param        page        path      int     true   "Page number"
-// @Param        language    query     string  false  "Language filter (default: en)"  Enums(en, ru, uz)
+// @Param        language    query     string  false  "Language, with fallback (default: en)"  Enums(en, ru, uz)
 // @Param        category    query     string  false  "Category filter"                Enums(blog, project)
-// @Param        title       query     string  false  "Search by blog title"
+// @Param        title       query     string  false  "Search query: words, quoted phrases, prefix*, -exclusions, title: or body: scoping"
+// @Param        tech        query     string  false  "Only projects using this technology"
+// @Param        from        query     string  false  "Only items created in or after this year or month (YYYY or YYYY-MM)"
+// @Param        to          query     string  false  "Only items created in or before this year or month (YYYY or YYYY-MM)"
+// @Param        highlight_open   query  string  false  "Marker inserted before matched terms: <mark>, <b>, <strong>, <em> or plain text (default <mark>)"
+// @Param        highlight_close  query  string  false  "Marker inserted after matched terms, closing the opening tag (default </mark>)"
+// @Param        snippet_length   query  int     false  "Number of tokens in the result snippet (1-64, default 24)"
+// @Param        If-None-Match      header  string  false  "ETag of a cached copy"
+// @Param        If-Modified-Since  header  string  false  "Last-Modified time of a cached copy"
 // @Produce      json
 // @Success      200  {object}  map[string]interface{}  "Blogs retrieved successfully"
+// @Header       200  {string}  ETag           "Weak validator of the listing"
+// @Header       200  {string}  Cache-Control  "Caching policy, configurable with CACHE_CONTROL_BLOGS"
+// @Success      304  "Not modified"
 // @Failure      400  {object}  map[string]string       "Invalid parameters"
 // @Failure      404  {object}  map[string]string       "No blogs found"
+// @Failure      422  {object}  map[string]interface{}  "Invalid from or to; lists each failing field"
 // @Failure      500  {object}  map[string]string       "Internal server error"
 // @Router       /blogs/{page} [get]
 func blogs(c *gin.Context) {
@@ -369,8 +605,44 @@ func blogs(c *gin.Context) {
 		return
 	}
 
-	contents, err := content.GetContents(title, int(page), language, category, featured)
+	hl, err := highlightOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	from, to := c.Query("from"), c.Query("to")
+	if _, _, err := content.DateRange(from, to); err != nil {
+		invalidInput(c, err)
+		return
+	}
+
+	// Searches are logged for analytics, so only plain listings are
+	// answered from the client's cache.
+	if title == "" {
+		etag, modified, err := content.ListState(c.Param("page") + "?" + c.Request.URL.RawQuery)
+		if err == nil && middlewares.NotModified(c, etag, modified) {
+			return
+		}
+	}
+
+	contents, err := content.GetContents(content.Filter{
+		Title:     title,
+		Page:      int(page),
+		Language:  language,
+		Category:  category,
+		Featured:  featured,
+		Tech:      strings.TrimSpace(c.Query("tech")),
+		From:      from,
+		To:        to,
+		Highlight: hl,
+	})
 	if err != nil {
+		var qe *content.QueryError
+		if errors.As(err, &qe) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid search query: " + qe.Error()})
+			return
+		}
 		if err.Error() == "no contents found" {
 			c.JSON(http.StatusNotFound, gin.H{"message": "No blogs found"})
 			return
@@ -395,6 +667,203 @@ func blogs(c *gin.Context) {
 	}
 }
 
+// search godoc
+// @Summary      Faceted search
+// @Description  Searches all languages and categories and returns one page of matches with counts per language, type, featured flag and tag.
+// @Tags         Content
+// @Param        q         query     string  true   "Search query: words, quoted phrases, prefix*, -exclusions, title: or body: scoping"
+// @Param        language  query     string  false  "Language filter"  Enums(en, ru, uz)
+// @Param        category  query     string  false  "Category filter"  Enums(blog, project)
+// @Param        featured  query     string  false  "Featured filter"  Enums(true, false)
+// @Param        tag       query     string  false  "Tag filter"
+// @Param        page      query     int     false  "Page number (default 1)"
+// @Param        highlight_open   query  string  false  "Marker inserted before matched terms: <mark>, <b>, <strong>, <em> or plain text (default <mark>)"
+// @Param        highlight_close  query  string  false  "Marker inserted after matched terms, closing the opening tag (default </mark>)"
+// @Param        snippet_length   query  int     false  "Number of tokens in the result snippet (1-64, default 24)"
+// @Produce      json
+// @Success      200  {object}  content.SearchResult
+// @Failure      400  {object}  map[string]string  "Invalid parameters"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /search [get]
+func search(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page number"})
+		return
+	}
+
+	f := content.SearchFilter{
+		Language: c.Query("language"),
+		Type:     c.Query("category"),
+		Featured: c.Query("featured"),
+		Tag:      strings.ToLower(strings.TrimSpace(c.Query("tag"))),
+	}
+	if f.Language != "" && f.Language != "en" && f.Language != "ru" && f.Language != "uz" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid language"})
+		return
+	}
+	if f.Type != "" && f.Type != "blog" && f.Type != "project" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category"})
+		return
+	}
+	if f.Featured != "" && f.Featured != "true" && f.Featured != "false" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid featured value"})
+		return
+	}
+
+	hl, err := highlightOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := content.Search(q, f, page, hl)
+	if err != nil {
+		var qe *content.QueryError
+		if errors.As(err, &qe) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid search query: " + qe.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Failed to search",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// searchReport godoc
+// @Summary      Search analytics report
+// @Description  Returns the most frequent search queries, queries without results and click-through by content ID.
+// @Security     TokenAuth
+// @Tags         admin
+// @Param        days   query  int  false  "Reporting window in days (1-365, default 30)"
+// @Param        limit  query  int  false  "Maximum rows per section (1-100, default 20)"
+// @Produce      json
+// @Success      200  {object}  analytics.Report
+// @Failure      400  {object}  map[string]string  "Invalid parameters"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /search/report [get]
+func searchReport(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+
+	report, err := analytics.GetReport(days, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Failed to build search report",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// suggest godoc
+// @Summary      Search suggestions
+// @Description  Returns titles and tags matching what the user has typed so far, tolerating small typos in titles. Titles of items not translated into the language are suggested in the language they fall back to.
+// @Tags         Content
+// @Param        q         query     string  true   "Typed text (2-64 characters)"
+// @Param        language  query     string  false  "Language (default: en)"  Enums(en, ru, uz)
+// @Param        limit     query     int     false  "Maximum suggestions per kind (1-10, default 5)"
+// @Produce      json
+// @Success      200  {object}  content.Suggestions
+// @Failure      400  {object}  map[string]string  "Invalid parameters"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /suggest [get]
+func suggest(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if n := len([]rune(q)); n < 2 || n > 64 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query must be between 2 and 64 characters"})
+		return
+	}
+
+	language := c.DefaultQuery("language", "en")
+	if language != "en" && language != "ru" && language != "uz" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid language"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if err != nil || limit < 1 || limit > 10 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+
+	s, err := content.Suggest(q, language, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Failed to fetch suggestions",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(http.StatusOK, s)
+}
+
+// highlightOptions reads the snippet marker and length overrides from the
+// query string, falling back to content.DefaultHighlight.
+// highlightTags maps the HTML tags allowed as highlight markers to their
+// closing tags. Clients render highlights as HTML, so any other marker must
+// be plain text.
+var highlightTags = map[string]string{
+	"<mark>": "</mark>", "<b>": "</b>", "<strong>": "</strong>", "<em>": "</em>",
+}
+
+func safeMarkers(open, closeTag string) bool {
+	if want, ok := highlightTags[open]; ok {
+		return closeTag == want
+	}
+	return !strings.ContainsAny(open+closeTag, `<>&"'`)
+}
+
+func highlightOptions(c *gin.Context) (content.Highlight, error) {
+	hl := content.DefaultHighlight()
+
+	if open, ok := c.GetQuery("highlight_open"); ok {
+		hl.Open = open
+	}
+	if closeTag, ok := c.GetQuery("highlight_close"); ok {
+		hl.Close = closeTag
+	}
+	if len(hl.Open) > 32 || len(hl.Close) > 32 {
+		return hl, errors.New("Highlight markers must be at most 32 characters")
+	}
+	if !safeMarkers(hl.Open, hl.Close) {
+		return hl, errors.New("Highlight markers must be a <mark>, <b>, <strong> or <em> tag pair or plain text without <, >, & or quotes")
+	}
+
+	if v := c.Query("snippet_length"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 64 {
+			return hl, errors.New("Invalid snippet_length (must be 1-64)")
+		}
+		hl.Tokens = n
+	}
+
+	return hl, nil
+}
+
 // register godoc
 // @Summary Sign up admin
 // @Description Register sign up in order to login
@@ -403,17 +872,25 @@ func blogs(c *gin.Context) {
 // @Produce json
 // @Param admin body admin.Address true "Admin sign up"
 // @Success 201 {object} map[string]string  "Signed up successfully"
-// @Failure 400 {object} map[string]string "The sign up must contain username, email, password"
+// @Failure 400 {object} map[string]string "Invalid request body"
+// @Failure 409 {object} map[string]string "Username or email is already taken"
+// @Failure 422 {object} map[string]interface{} "Validation failed; lists each failing field"
 // @Failure 500 {object} map[string]string  "Could not sign up. Try again later"
 // @Router /signup [post]
 func register(c *gin.Context) {
 	var a admin.Address
-	err := c.ShouldBindJSON(&a)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "The sign up must contain username, email, password"})
+	if !bindJSON(c, &a) {
+		return
+	}
+	if err := a.Validate(); err != nil {
+		invalidInput(c, err)
+		return
+	}
+	err := a.SignUp()
+	if errors.Is(err, admin.ErrTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Username or email is already taken"})
 		return
 	}
-	err = a.SignUp()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not sign up. Try again later"})
 		return
@@ -430,15 +907,18 @@ func register(c *gin.Context) {
 // @Produce json
 // @Param admin body admin.Login true "Admin login"
 // @Success 200 {object} map[string]string  "Logged in successfully"
-// @Failure 400 {object} map[string]string "Invalid login"
+// @Failure 400 {object} map[string]string "Invalid request body"
 // @Failure 400 {object} map[string]string  "Invalid input"
+// @Failure 422 {object} map[string]interface{} "Validation failed; lists each failing field"
 // @Failure 500 {object} map[string]string "Could not generate token"
 // @Router /login [post]
 func login(c *gin.Context) {
 	var l admin.Login
-	err := c.ShouldBindJSON(&l)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid login"})
+	if !bindJSON(c, &l) {
+		return
+	}
+	if err := l.Validate(); err != nil {
+		invalidInput(c, err)
 		return
 	}
 
@@ -447,7 +927,7 @@ func login(c *gin.Context) {
 		Email:    l.Login,
 		Password: l.Password,
 	}
-	err = s.Login()
+	err := s.Login()
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
 		return
@@ -464,13 +944,23 @@ func login(c *gin.Context) {
 
 // getSingle godoc
 // @Summary Get single content by ID
-// @Description Returns one content item (blog or project) by its ID
+// @Description Returns one content item (blog or project) by its ID. Drafts and archived items are only returned to an admin. With language, the item's published translation into that language is returned instead, or into the next language of its LANGUAGE_FALLBACK chain; requested_language is then set if the language served differs.
 // @Tags content
 // @Produce json
 // @Param id path int true "Blog ID"
+// @Param language query string false "Preferred language" Enums(en, ru, uz)
+// @Param q query string false "Search query the item was opened from, recorded for click-through statistics"
 // @Success 200 {object} content.Content
-// @Failure 400 {object} map[string]string "Invalid blog ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Param If-Modified-Since header string false "Last-Modified time of a cached copy"
+// @Header 200 {string} ETag "State of the content including its reactions, also accepted by If-Match on update and delete"
+// @Header 200 {string} Content-Language "Language of the content served"
+// @Header 200 {string} Last-Modified "Time of the last change to the content"
+// @Header 200 {string} Cache-Control "Caching policy, configurable with CACHE_CONTROL_BLOG"
+// @Success 304 "Not modified"
+// @Failure 400 {object} map[string]string "Invalid blog ID or language"
 // @Failure 404 {object} map[string]string "Blog not found"
+// @Failure 500 {object} map[string]string "Failed to fetch blog"
 // @Router /blog/{id} [get]
 func getSingle(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -479,13 +969,98 @@ func getSingle(c *gin.Context) {
 		return
 	}
 
+	language := c.Query("language")
+	if language != "" {
+		if !content.ValidLanguage(language) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid language"})
+			return
+		}
+		if id, err = content.Translation(id, language); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch blog"})
+			return
+		}
+	}
+
+	rev, err := content.Revision(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+
+	// Drafts and archived items can only be previewed by an admin, and
+	// such previews must not end up in a shared cache.
+	if rev.Status != content.StatusPublished {
+		if utils.Check(c.GetHeader("Authorization")) != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+			return
+		}
+		c.Header("Cache-Control", "private, no-store")
+	}
+
+	if q := c.Query("q"); q != "" {
+		analytics.LogClick(q, id)
+	}
+
+	if middlewares.NotModified(c, rev.State(), rev.Modified()) {
+		return
+	}
+
 	content, err := content.GetById(id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
 		return
 	}
 
+	if language != "" && content.Language != language {
+		content.RequestedLanguage = language
+	}
+
+	c.Header("Content-Language", content.Language)
 	c.JSON(http.StatusOK, content)
 }
 
+// related godoc
+// @Summary Get related content
+// @Description Returns "read next" items as listed in the item's language, falling back along LANGUAGE_FALLBACK like GET /blogs/{page}, ranked by text similarity and shared tags. Translations of the item are left out.
+// @Tags content
+// @Produce json
+// @Param id path int true "Blog ID"
+// @Param limit query int false "Maximum number of items (1-20, default 4)"
+// @Success 200 {object} map[string]interface{} "Related content"
+// @Failure 400 {object} map[string]string "Invalid blog ID"
+// @Failure 404 {object} map[string]string "Blog not found"
+// @Failure 500 {object} map[string]string "Failed to fetch related content"
+// @Router /blog/{id}/related [get]
+func related(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blog ID"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "4"))
+	if err != nil || limit < 1 || limit > 20 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+
+	contents, err := content.GetRelated(id, limit)
+	if err != nil {
+		if err.Error() == "blog not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Failed to fetch related content",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Related content fetched successfully",
+		"contents": contents,
+	})
+}
+
 //done
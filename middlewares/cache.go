@@ -0,0 +1,107 @@
+package middlewares
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheControl sets the Cache-Control header of successful responses to the
+// policy in the environment variable env, or to def if it is unset. A
+// policy of "off" sends no header. Error responses are marked no-store so
+// that a CDN never caches them.
+func CacheControl(env, def string) gin.HandlerFunc {
+	policy := os.Getenv(env)
+	if policy == "" {
+		policy = def
+	}
+	if strings.EqualFold(policy, "off") {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		c.Writer = &cacheWriter{ResponseWriter: c.Writer, policy: policy}
+		c.Next()
+	}
+}
+
+type cacheWriter struct {
+	gin.ResponseWriter
+	policy string
+}
+
+func (w *cacheWriter) setPolicy(code int) {
+	if w.Written() || w.Header().Get("Cache-Control") != "" {
+		return
+	}
+	if code < http.StatusBadRequest {
+		w.Header().Set("Cache-Control", w.policy)
+	} else {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+}
+
+func (w *cacheWriter) WriteHeader(code int) {
+	w.setPolicy(code)
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheWriter) WriteHeaderNow() {
+	w.setPolicy(w.Status())
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *cacheWriter) Write(data []byte) (int, error) {
+	w.setPolicy(w.Status())
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cacheWriter) WriteString(s string) (int, error) {
+	w.setPolicy(w.Status())
+	return w.ResponseWriter.WriteString(s)
+}
+
+// NotModified sets the ETag and Last-Modified headers and reports whether
+// the conditional headers of the request show that the client already has
+// this representation, in which case it responds with 304. As in RFC 9110,
+// If-None-Match takes precedence over If-Modified-Since and is compared
+// weakly.
+func NotModified(c *gin.Context, etag string, modified time.Time) bool {
+	if etag != "" {
+		c.Header("ETag", etag)
+	}
+	if !modified.IsZero() {
+		c.Header("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return false
+	}
+
+	fresh := false
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		for _, t := range strings.Split(inm, ",") {
+			t = strings.TrimSpace(t)
+			if t == "*" || (etag != "" && weakTag(t) == weakTag(etag)) {
+				fresh = true
+				break
+			}
+		}
+	} else if ims := c.GetHeader("If-Modified-Since"); ims != "" && !modified.IsZero() {
+		if t, err := http.ParseTime(ims); err == nil && !modified.Truncate(time.Second).After(t) {
+			fresh = true
+		}
+	}
+
+	if fresh {
+		c.AbortWithStatus(http.StatusNotModified)
+	}
+	return fresh
+}
+
+func weakTag(t string) string {
+	return strings.TrimPrefix(t, "W/")
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"example.com/portfolio/content"
+	"example.com/portfolio/newsletter"
+	"github.com/gin-gonic/gin"
+)
+
+// subscribe godoc
+// @Summary      Subscribe to the newsletter
+// @Description  Mails a confirmation link to the address; new posts in the chosen language are only sent once it is confirmed. The response is the same whether or not the address is already subscribed.
+// @Tags         newsletter
+// @Accept       json
+// @Produce      json
+// @Param        subscription  body      newsletter.Subscription  true  "Email and language (en, ru or uz)"
+// @Success      202           {object}  map[string]string       "Confirmation mail sent"
+// @Failure      400           {object}  map[string]string       "Invalid request body"
+// @Failure      422           {object}  map[string]interface{}  "Validation failed; lists each failing field"
+// @Failure      429           {object}  map[string]string       "Daily subscription limit reached"
+// @Failure      500           {object}  map[string]string       "Could not subscribe"
+// @Router       /newsletter/subscribe [post]
+func subscribe(c *gin.Context) {
+	var s newsletter.Subscription
+	if !bindJSON(c, &s) {
+		return
+	}
+	if err := s.Validate(); err != nil {
+		invalidInput(c, err)
+		return
+	}
+
+	ip := c.ClientIP()
+	ok, err := newsletter.CanSubscribe(ip)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Daily subscription limit reached"})
+		return
+	}
+
+	if err := s.Subscribe(ip); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not subscribe. Try again later"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Check your inbox to confirm your subscription"})
+}
+
+// confirmSubscription godoc
+// @Summary      Confirm a newsletter subscription
+// @Description  Target of the link in the confirmation mail
+// @Tags         newsletter
+// @Produce      json
+// @Param        token  query     string  true  "Token from the confirmation link"
+// @Success      200    {object}  map[string]interface{}  "Subscription confirmed"
+// @Failure      400    {object}  map[string]string       "Invalid or expired link"
+// @Failure      409    {object}  map[string]string       "Subscription is not awaiting confirmation"
+// @Failure      500    {object}  map[string]string       "Could not confirm"
+// @Router       /newsletter/confirm [get]
+func confirmSubscription(c *gin.Context) {
+	s, err := newsletter.Confirm(c.Query("token"))
+	switch {
+	case errors.Is(err, newsletter.ErrInvalidToken):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, newsletter.ErrNotPending):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not confirm the subscription"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Your subscription is confirmed",
+		"email":    s.Email,
+		"language": s.Language,
+	})
+}
+
+// unsubscribePage godoc
+// @Summary      Show the unsubscribe page
+// @Description  Target of the link in every newsletter mail. Shows a page asking to confirm, whose form posts to the same URL; opening the link does not unsubscribe.
+// @Tags         newsletter
+// @Produce      html
+// @Param        token  query     string  true  "Token from the unsubscribe link"
+// @Success      200    {string}  string  "Confirmation page"
+// @Failure      400    {string}  string  "Invalid or expired link"
+// @Failure      500    {string}  string  "Could not check the link"
+// @Router       /newsletter/unsubscribe [get]
+func unsubscribePage(c *gin.Context) {
+	s, err := newsletter.CheckUnsubscribe(c.Query("token"))
+	switch {
+	case errors.Is(err, newsletter.ErrInvalidToken):
+		writePage(c, http.StatusBadRequest, newsletter.InvalidLinkPage)
+	case err != nil:
+		c.String(http.StatusInternalServerError, "Could not check the link")
+	default:
+		writePage(c, http.StatusOK, func(w io.Writer) error {
+			return newsletter.UnsubscribePage(w, s)
+		})
+	}
+}
+
+// unsubscribe godoc
+// @Summary      Unsubscribe from the newsletter
+// @Description  Posted by the unsubscribe page and by mail clients that support one-click unsubscribing (RFC 8058). Answers with a page when the client accepts HTML, otherwise with JSON.
+// @Tags         newsletter
+// @Produce      json,html
+// @Param        token  query     string  true  "Token from the unsubscribe link"
+// @Success      200    {object}  map[string]string  "Unsubscribed"
+// @Failure      400    {object}  map[string]string  "Invalid or expired link"
+// @Failure      500    {object}  map[string]string  "Could not unsubscribe"
+// @Router       /newsletter/unsubscribe [post]
+func unsubscribe(c *gin.Context) {
+	html := c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML
+	s, err := newsletter.Unsubscribe(c.Query("token"))
+	switch {
+	case errors.Is(err, newsletter.ErrInvalidToken) && html:
+		writePage(c, http.StatusBadRequest, newsletter.InvalidLinkPage)
+	case errors.Is(err, newsletter.ErrInvalidToken):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not unsubscribe"})
+	case html:
+		writePage(c, http.StatusOK, func(w io.Writer) error {
+			return newsletter.UnsubscribedPage(w, s.Language)
+		})
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "You have been unsubscribed"})
+	}
+}
+
+// writePage answers with the HTML page write renders.
+func writePage(c *gin.Context, status int, write func(io.Writer) error) {
+	var b bytes.Buffer
+	if err := write(&b); err != nil {
+		c.String(http.StatusInternalServerError, "Could not render the page")
+		return
+	}
+	c.Data(status, "text/html; charset=utf-8", b.Bytes())
+}
+
+// listSubscribers godoc
+// @Summary      List newsletter subscribers
+// @Description  Returns subscribers, newest first, with the number of subscribers per status
+// @Security     TokenAuth
+// @Tags         newsletter
+// @Produce      json
+// @Param        status    query     string  false  "Subscription status"  Enums(pending, confirmed, unsubscribed)
+// @Param        language  query     string  false  "Language"  Enums(en, ru, uz)
+// @Param        page      query     int     false  "Page number (default 1)"
+// @Success      200       {object}  map[string]interface{}  "Subscribers and counts"
+// @Failure      400       {object}  map[string]string       "Invalid parameters"
+// @Failure      500       {object}  map[string]string       "Failed to fetch subscribers"
+// @Router       /newsletter/subscribers [get]
+func listSubscribers(c *gin.Context) {
+	status := c.Query("status")
+	if status != "" && !newsletter.ValidStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+
+	language := c.Query("language")
+	if language != "" && !content.ValidLanguage(language) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid language"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page number"})
+		return
+	}
+
+	list, counts, err := newsletter.List(status, language, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch subscribers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"subscribers": list, "counts": counts})
+}
+
+// notifyPublished announces cnt to subscribers if the change that was
+// just saved published it; before is its status before the change, empty
+// for new content. Imports do not call it, so that importing old posts
+// does not mail them all.
+func notifyPublished(before string, cnt content.Content) {
+	if before != content.StatusPublished && cnt.Status == content.StatusPublished {
+		newsletter.NotifyPublished(cnt)
+	}
+}
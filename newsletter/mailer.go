@@ -0,0 +1,146 @@
+package newsletter
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	// Headers are added to the standard ones, e.g. List-Unsubscribe.
+	Headers map[string]string
+}
+
+type Mailer interface {
+	Send(m Message) error
+}
+
+// SMTP sends mail through an SMTP server, using STARTTLS when the server
+// offers it. Authentication is skipped when Username is empty, e.g. for a
+// local test server such as MailHog.
+type SMTP struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (s SMTP) Send(m Message) error {
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", s.From, err)
+	}
+	data, err := compose(from, m)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+	addr := net.JoinHostPort(s.Host, s.Port)
+	if err := smtp.SendMail(addr, auth, from.Address, []string{m.To}, data); err != nil {
+		return fmt.Errorf("failed to send mail to %s: %w", m.To, err)
+	}
+	return nil
+}
+
+// Log writes mail to the log instead of sending it. It is used when no
+// SMTP server is configured, so that links can be copied in development.
+type Log struct{}
+
+func (Log) Send(m Message) error {
+	log.Printf("📧 Mail to %s: %s\n%s", m.To, m.Subject, m.Text)
+	return nil
+}
+
+var mailer Mailer = Log{}
+
+// ConfigureMailer sends mail through the SMTP server in SMTP_HOST, with
+// SMTP_PORT (default 587), SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM.
+// Without SMTP_HOST, mail is only logged.
+func ConfigureMailer() {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		log.Println("⚠️ SMTP_HOST not set, newsletter mail is only logged")
+		return
+	}
+	s := SMTP{
+		Host:     host,
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+	if s.Port == "" {
+		s.Port = "587"
+	}
+	if s.From == "" {
+		s.From = "newsletter@" + host
+	}
+	SetMailer(s)
+}
+
+func SetMailer(m Mailer) {
+	mailer = m
+}
+
+// compose builds a UTF-8 message with a quoted-printable body.
+func compose(from *mail.Address, m Message) ([]byte, error) {
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	domain := from.Address[strings.LastIndex(from.Address, "@")+1:]
+
+	headers := map[string]string{
+		"From":                      from.String(),
+		"To":                        m.To,
+		"Subject":                   mime.QEncoding.Encode("utf-8", m.Subject),
+		"Date":                      time.Now().Format(time.RFC1123Z),
+		"Message-ID":                "<" + hex.EncodeToString(id) + "@" + domain + ">",
+		"MIME-Version":              "1.0",
+		"Content-Type":              "text/plain; charset=utf-8",
+		"Content-Transfer-Encoding": "quoted-printable",
+	}
+	for k, v := range m.Headers {
+		headers[k] = v
+	}
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b bytes.Buffer
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s: %s\r\n", k, headers[k])
+	}
+	b.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&b)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(m.Text, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,445 @@
+// Package newsletter lets readers subscribe to new posts in their language.
+// Subscriptions are confirmed through an emailed link (double opt-in) and
+// every mail carries a link to unsubscribe.
+package newsletter
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"example.com/portfolio/content"
+	"example.com/portfolio/db"
+	"example.com/portfolio/utils"
+	"example.com/portfolio/validation"
+)
+
+const (
+	StatusPending      = "pending"
+	StatusConfirmed    = "confirmed"
+	StatusUnsubscribed = "unsubscribed"
+
+	purposeConfirm = "newsletter-confirm"
+
+	confirmTTL     = 48 * time.Hour
+	unsubscribeTTL = 180 * 24 * time.Hour
+	// A confirmation mail is sent again at most every resendInterval,
+	// and one IP can subscribe at most dailyLimit times a day.
+	resendInterval = 10 * time.Minute
+	dailyLimit     = 5
+	pageSize       = 50
+)
+
+var (
+	ErrInvalid      = errors.New("invalid subscription")
+	ErrInvalidToken = errors.New("the link is invalid or has expired")
+	ErrNotPending   = errors.New("the subscription is not awaiting confirmation; subscribe again")
+)
+
+type Subscriber struct {
+	ID          int64   `json:"id"`
+	Email       string  `json:"email"`
+	Language    string  `json:"language"`
+	Status      string  `json:"status"`
+	CreatedAt   string  `json:"created_at"`
+	ConfirmedAt *string `json:"confirmed_at,omitempty"`
+}
+
+// Subscription is the input of Subscribe.
+type Subscription struct {
+	Email    string `json:"email"`
+	Language string `json:"language"`
+}
+
+func (s *Subscription) Validate() error {
+	s.Email = strings.ToLower(strings.TrimSpace(s.Email))
+	var errs validation.Errors
+	if errs.Required("email", s.Email) {
+		if errs.MaxLength("email", s.Email, 254) {
+			errs.Email("email", s.Email)
+		}
+	}
+	if errs.Required("language", s.Language) {
+		errs.OneOf("language", s.Language, content.Languages)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalid, errs)
+	}
+	return nil
+}
+
+// Subscribe records a pending subscription and mails the confirmation
+// link. Nothing happens for a confirmed subscriber, whose language can
+// only change by unsubscribing and subscribing again, nor for a pending
+// one mailed within resendInterval. The caller cannot tell these cases
+// apart, so the endpoint does not reveal who is subscribed. Every call
+// counts towards the daily limit of ip.
+func (s *Subscription) Subscribe(ip string) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if err := recordAttempt(ip); err != nil {
+		return err
+	}
+
+	var (
+		status string
+		sentAt sql.NullString
+	)
+	err := db.DB.QueryRowContext(context.Background(),
+		"SELECT status, confirmation_sent_at FROM subscribers WHERE email = ?", s.Email).
+		Scan(&status, &sentAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		_, err = db.DB.ExecContext(context.Background(), `
+			INSERT INTO subscribers (email, language, status, ip, confirmation_sent_at)
+			VALUES (?, ?, 'pending', ?, datetime('now'))
+		`, s.Email, s.Language, ip)
+	case err != nil:
+		return fmt.Errorf("failed to get subscriber: %w", err)
+	case status == StatusConfirmed, status == StatusPending && recent(sentAt.String, resendInterval):
+		return nil
+	default:
+		_, err = db.DB.ExecContext(context.Background(), `
+			UPDATE subscribers
+			SET language = ?, status = 'pending', ip = ?, confirmation_sent_at = datetime('now')
+			WHERE email = ?
+		`, s.Language, ip, s.Email)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save subscriber: %w", err)
+	}
+
+	token, err := utils.GenerateActionToken(purposeConfirm, s.Email, confirmTTL)
+	if err != nil {
+		return err
+	}
+	t := texts(s.Language)
+	return mailer.Send(Message{
+		To:      s.Email,
+		Subject: t.confirmSubject,
+		Text:    fmt.Sprintf(t.confirmBody, link("confirm", token)),
+	})
+}
+
+// CanSubscribe reports whether ip may subscribe again today. Attempts are
+// counted, not addresses, so asking for the same address over and over
+// uses up the limit as well.
+func CanSubscribe(ip string) (bool, error) {
+	var count int
+	err := db.DB.QueryRowContext(context.Background(), `
+		SELECT COUNT(*) FROM subscribe_attempts
+		WHERE ip = ? AND DATE(created_at) = DATE('now')
+	`, ip).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count < dailyLimit, nil
+}
+
+// recordAttempt counts a call to Subscribe from ip and forgets the
+// attempts of earlier days.
+func recordAttempt(ip string) error {
+	ctx := context.Background()
+	if _, err := db.DB.ExecContext(ctx,
+		"DELETE FROM subscribe_attempts WHERE DATE(created_at) < DATE('now')"); err != nil {
+		return fmt.Errorf("failed to prune subscribe attempts: %w", err)
+	}
+	if _, err := db.DB.ExecContext(ctx,
+		"INSERT INTO subscribe_attempts (ip) VALUES (?)", ip); err != nil {
+		return fmt.Errorf("failed to record subscribe attempt: %w", err)
+	}
+	return nil
+}
+
+// Confirm confirms the subscription a confirmation link was sent for.
+// Confirming twice is not an error.
+func Confirm(token string) (Subscriber, error) {
+	email, err := utils.CheckActionToken(purposeConfirm, token)
+	if err != nil {
+		return Subscriber{}, ErrInvalidToken
+	}
+
+	_, err = db.DB.ExecContext(context.Background(), `
+		UPDATE subscribers SET status = 'confirmed', confirmed_at = datetime('now')
+		WHERE email = ? AND status = 'pending'
+	`, email)
+	if err != nil {
+		return Subscriber{}, fmt.Errorf("failed to confirm subscriber: %w", err)
+	}
+
+	s, err := get(email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return s, ErrInvalidToken
+	}
+	if err == nil && s.Status != StatusConfirmed {
+		return s, ErrNotPending
+	}
+	return s, err
+}
+
+// CheckUnsubscribe returns the subscriber an unsubscribe link was sent
+// to, without unsubscribing them.
+func CheckUnsubscribe(token string) (Subscriber, error) {
+	var s Subscriber
+	err := db.DB.QueryRowContext(context.Background(), `
+		SELECT s.id, s.email, s.language, s.status, s.created_at, s.confirmed_at
+		FROM unsubscribe_tokens t JOIN subscribers s ON s.id = t.subscriber_id
+		WHERE t.token_hash = ? AND t.expires_at > datetime('now')
+	`, hashToken(token)).Scan(&s.ID, &s.Email, &s.Language, &s.Status, &s.CreatedAt, &s.ConfirmedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return s, ErrInvalidToken
+	}
+	if err != nil {
+		return s, fmt.Errorf("failed to check unsubscribe link: %w", err)
+	}
+	return s, nil
+}
+
+// Unsubscribe ends the subscription an unsubscribe link was sent for.
+// Unsubscribing twice is not an error.
+func Unsubscribe(token string) (Subscriber, error) {
+	s, err := CheckUnsubscribe(token)
+	if err != nil {
+		return s, err
+	}
+
+	_, err = db.DB.ExecContext(context.Background(), `
+		UPDATE subscribers SET status = 'unsubscribed', unsubscribed_at = datetime('now')
+		WHERE id = ? AND status != 'unsubscribed'
+	`, s.ID)
+	if err != nil {
+		return s, fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+	s.Status = StatusUnsubscribed
+	return s, nil
+}
+
+// unsubscribeToken stores a new unsubscribe link token for subscriber id
+// and returns it. The token is random; only its hash is stored, and it
+// expires after unsubscribeTTL.
+func unsubscribeToken(id int64) (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+
+	_, err := db.DB.ExecContext(context.Background(), `
+		INSERT INTO unsubscribe_tokens (token_hash, subscriber_id, expires_at)
+		VALUES (?, ?, datetime('now', ?))
+	`, hashToken(token), id, fmt.Sprintf("+%d seconds", int(unsubscribeTTL.Seconds())))
+	if err != nil {
+		return "", fmt.Errorf("failed to save unsubscribe token: %w", err)
+	}
+	return token, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// List returns a page of subscribers, newest first, and the number of
+// subscribers per status. Empty filters match everything.
+func List(status, language string, page int) ([]Subscriber, map[string]int, error) {
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT id, email, language, status, created_at, confirmed_at
+		FROM subscribers
+		WHERE (? = '' OR status = ?) AND (? = '' OR language = ?)
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?
+	`, status, status, language, language, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	list := []Subscriber{}
+	for rows.Next() {
+		var s Subscriber
+		if err := rows.Scan(&s.ID, &s.Email, &s.Language, &s.Status, &s.CreatedAt, &s.ConfirmedAt); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan subscriber: %w", err)
+		}
+		list = append(list, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	counts := map[string]int{StatusPending: 0, StatusConfirmed: 0, StatusUnsubscribed: 0}
+	countRows, err := db.DB.QueryContext(context.Background(), `
+		SELECT status, COUNT(*) FROM subscribers
+		WHERE (? = '' OR language = ?)
+		GROUP BY status
+	`, language, language)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count subscribers: %w", err)
+	}
+	defer countRows.Close()
+	for countRows.Next() {
+		var (
+			s string
+			n int
+		)
+		if err := countRows.Scan(&s, &n); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan count: %w", err)
+		}
+		counts[s] = n
+	}
+	return list, counts, countRows.Err()
+}
+
+func ValidStatus(status string) bool {
+	return status == StatusPending || status == StatusConfirmed || status == StatusUnsubscribed
+}
+
+func get(email string) (Subscriber, error) {
+	var s Subscriber
+	err := db.DB.QueryRowContext(context.Background(), `
+		SELECT id, email, language, status, created_at, confirmed_at
+		FROM subscribers WHERE email = ?
+	`, email).Scan(&s.ID, &s.Email, &s.Language, &s.Status, &s.CreatedAt, &s.ConfirmedAt)
+	return s, err
+}
+
+// NotifyPublished mails content c to the confirmed subscribers of its
+// language, in the background. Each item is announced at most once, even
+// if it is unpublished and published again; if no mail could be delivered
+// it is tried again the next time c is published.
+func NotifyPublished(c content.Content) {
+	go func() {
+		if err := notify(c); err != nil {
+			log.Printf("⚠️ Could not send newsletter for content %d: %v", c.ID, err)
+		}
+	}()
+}
+
+// notify claims c in newsletter_sent with a NULL sent_at, so that a
+// concurrent publish does not mail it too, and sets sent_at once the
+// mails are delivered. The claim is dropped again if it fails before any
+// mail was delivered.
+func notify(c content.Content) (err error) {
+	ctx := context.Background()
+	res, err := db.DB.ExecContext(ctx,
+		"INSERT OR IGNORE INTO newsletter_sent (content_id, sent_at) VALUES (?, NULL)", c.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil
+	}
+	sent := 0
+	defer func() {
+		if err == nil || sent > 0 {
+			return
+		}
+		if _, dropErr := db.DB.ExecContext(ctx,
+			"DELETE FROM newsletter_sent WHERE content_id = ? AND sent_at IS NULL", c.ID); dropErr != nil {
+			log.Printf("⚠️ Could not release newsletter claim for content %d: %v", c.ID, dropErr)
+		}
+	}()
+
+	if _, err := db.DB.ExecContext(ctx,
+		"DELETE FROM unsubscribe_tokens WHERE expires_at <= datetime('now')"); err != nil {
+		return fmt.Errorf("failed to prune unsubscribe tokens: %w", err)
+	}
+
+	rows, err := db.DB.QueryContext(ctx,
+		"SELECT id, email FROM subscribers WHERE status = 'confirmed' AND language = ?", c.Language)
+	if err != nil {
+		return err
+	}
+	type recipient struct {
+		id    int64
+		email string
+	}
+	var recipients []recipient
+	for rows.Next() {
+		var r recipient
+		if err := rows.Scan(&r.id, &r.email); err != nil {
+			rows.Close()
+			return err
+		}
+		recipients = append(recipients, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	t := texts(c.Language)
+	var lastErr error
+	for _, r := range recipients {
+		token, err := unsubscribeToken(r.id)
+		if err != nil {
+			return err
+		}
+		unsubscribe := link("unsubscribe", token)
+		err = mailer.Send(Message{
+			To:      r.email,
+			Subject: fmt.Sprintf(t.postSubject, c.Title),
+			Text:    fmt.Sprintf(t.postBody, c.Title, postURL(c), unsubscribe),
+			Headers: map[string]string{
+				"List-Unsubscribe":      "<" + unsubscribe + ">",
+				"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+			},
+		})
+		if err != nil {
+			log.Printf("⚠️ %v", err)
+			lastErr = err
+			continue
+		}
+		sent++
+	}
+	if sent == 0 && lastErr != nil {
+		return fmt.Errorf("no mail could be delivered: %w", lastErr)
+	}
+
+	_, err = db.DB.ExecContext(ctx,
+		"UPDATE newsletter_sent SET recipients = ?, sent_at = datetime('now') WHERE content_id = ?", sent, c.ID)
+	return err
+}
+
+// link returns the API URL of a confirm or unsubscribe link.
+func link(action, token string) string {
+	return publicURL() + "/newsletter/" + action + "?token=" + url.QueryEscape(token)
+}
+
+// publicURL is the address the API is reachable at, from PUBLIC_URL.
+func publicURL() string {
+	if base := strings.TrimRight(os.Getenv("PUBLIC_URL"), "/"); base != "" {
+		return base
+	}
+	return "http://localhost:8080"
+}
+
+// postURL links to c on the website. NEWSLETTER_POST_URL is a template
+// with {id}, {language} and {type}, e.g. https://example.com/{language}/blog/{id};
+// without it the post is linked in the API.
+func postURL(c content.Content) string {
+	tmpl := os.Getenv("NEWSLETTER_POST_URL")
+	if tmpl == "" {
+		return fmt.Sprintf("%s/blog/%d", publicURL(), c.ID)
+	}
+	return strings.NewReplacer(
+		"{id}", fmt.Sprint(c.ID),
+		"{language}", c.Language,
+		"{type}", c.Type,
+	).Replace(tmpl)
+}
+
+// recent reports whether the SQLite time t is less than d ago.
+func recent(t string, d time.Duration) bool {
+	sent, err := time.ParseInLocation("2006-01-02 15:04:05", t, time.UTC)
+	return err == nil && time.Since(sent) < d
+}
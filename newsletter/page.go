@@ -0,0 +1,66 @@
+package newsletter
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+)
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="{{.Language}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Text}}</p>
+{{- if .Button}}
+<form method="post">
+<button type="submit">{{.Button}}</button>
+</form>
+{{- end}}
+</body>
+</html>
+`))
+
+type page struct {
+	Language string
+	Title    string
+	Text     string
+	Button   string
+}
+
+// UnsubscribePage writes the page an unsubscribe link opens, which asks s
+// to confirm. The form posts back to the same URL, so opening the link,
+// as mail scanners do, does not unsubscribe anyone.
+func UnsubscribePage(w io.Writer, s Subscriber) error {
+	t := pageText(s.Language)
+	return pageTemplate.Execute(w, page{
+		Language: s.Language,
+		Title:    t.unsubscribeTitle,
+		Text:     fmt.Sprintf(t.unsubscribeQuestion, s.Email),
+		Button:   t.unsubscribeButton,
+	})
+}
+
+// UnsubscribedPage writes the page shown after unsubscribing.
+func UnsubscribedPage(w io.Writer, language string) error {
+	t := pageText(language)
+	return pageTemplate.Execute(w, page{
+		Language: language,
+		Title:    t.unsubscribedTitle,
+		Text:     t.unsubscribedText,
+	})
+}
+
+// InvalidLinkPage writes the page shown for an invalid or expired link.
+func InvalidLinkPage(w io.Writer) error {
+	t := pageText("en")
+	return pageTemplate.Execute(w, page{
+		Language: "en",
+		Title:    t.unsubscribeTitle,
+		Text:     t.invalidText,
+	})
+}
@@ -0,0 +1,86 @@
+package newsletter
+
+// mailTexts are the mails in one language. The confirm body takes the
+// confirmation link; the post subject the title, and the post body the
+// title, the post link and the unsubscribe link.
+type mailTexts struct {
+	confirmSubject string
+	confirmBody    string
+	postSubject    string
+	postBody       string
+}
+
+var mails = map[string]mailTexts{
+	"en": {
+		confirmSubject: "Confirm your subscription",
+		confirmBody: "Please confirm that you want to receive new posts by email:\n\n%s\n\n" +
+			"The link is valid for 48 hours. If you did not subscribe, ignore this mail.\n",
+		postSubject: "New post: %s",
+		postBody:    "%s\n\nRead it here: %s\n\n--\nUnsubscribe: %s\n",
+	},
+	"ru": {
+		confirmSubject: "Подтвердите подписку",
+		confirmBody: "Подтвердите, что хотите получать новые публикации по почте:\n\n%s\n\n" +
+			"Ссылка действует 48 часов. Если вы не подписывались, просто проигнорируйте это письмо.\n",
+		postSubject: "Новая публикация: %s",
+		postBody:    "%s\n\nЧитать: %s\n\n--\nОтписаться: %s\n",
+	},
+	"uz": {
+		confirmSubject: "Obunani tasdiqlang",
+		confirmBody: "Yangi maqolalarni elektron pochta orqali olishni xohlayotganingizni tasdiqlang:\n\n%s\n\n" +
+			"Havola 48 soat davomida amal qiladi. Agar obuna bo'lmagan bo'lsangiz, bu xatni e'tiborsiz qoldiring.\n",
+		postSubject: "Yangi maqola: %s",
+		postBody:    "%s\n\nO'qish: %s\n\n--\nObunani bekor qilish: %s\n",
+	},
+}
+
+func texts(language string) mailTexts {
+	if t, ok := mails[language]; ok {
+		return t
+	}
+	return mails["en"]
+}
+
+// pageTexts are the texts of the unsubscribe page in one language.
+type pageTexts struct {
+	unsubscribeTitle    string
+	unsubscribeQuestion string
+	unsubscribeButton   string
+	unsubscribedTitle   string
+	unsubscribedText    string
+	invalidText         string
+}
+
+var pages = map[string]pageTexts{
+	"en": {
+		unsubscribeTitle:    "Unsubscribe",
+		unsubscribeQuestion: "Stop sending new posts to %s?",
+		unsubscribeButton:   "Unsubscribe",
+		unsubscribedTitle:   "Unsubscribed",
+		unsubscribedText:    "You will no longer receive new posts by email.",
+		invalidText:         "The link is invalid or has expired.",
+	},
+	"ru": {
+		unsubscribeTitle:    "Отписка",
+		unsubscribeQuestion: "Больше не присылать новые публикации на %s?",
+		unsubscribeButton:   "Отписаться",
+		unsubscribedTitle:   "Вы отписались",
+		unsubscribedText:    "Новые публикации больше не будут приходить на почту.",
+		invalidText:         "Ссылка недействительна или устарела.",
+	},
+	"uz": {
+		unsubscribeTitle:    "Obunani bekor qilish",
+		unsubscribeQuestion: "Yangi maqolalar %s manziliga yuborilmasinmi?",
+		unsubscribeButton:   "Obunani bekor qilish",
+		unsubscribedTitle:   "Obuna bekor qilindi",
+		unsubscribedText:    "Yangi maqolalar endi elektron pochtaga yuborilmaydi.",
+		invalidText:         "Havola yaroqsiz yoki muddati o'tgan.",
+	},
+}
+
+func pageText(language string) pageTexts {
+	if t, ok := pages[language]; ok {
+		return t
+	}
+	return pages["en"]
+}
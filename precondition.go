@@ -0,0 +1,50 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"example.com/portfolio/content"
+	"github.com/gin-gonic/gin"
+)
+
+// ifMatch reports whether the If-Match header, if present, names the
+// current revision of cnt, either as its ETag or as the State returned by
+// GET. If it does not, it responds with 412 and the current version. Weak
+// entity tags never match, as required for If-Match.
+func ifMatch(c *gin.Context, cnt content.Content) bool {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		return true
+	}
+
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || cnt.SameRevision(t) {
+			return true
+		}
+	}
+
+	preconditionFailed(c, cnt)
+	return false
+}
+
+// versionConflict responds to content.ErrVersionConflict with the revision
+// that is stored now.
+func versionConflict(c *gin.Context, id int64) {
+	cnt, err := content.GetById(id)
+	if err != nil {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": "Content has been deleted in the meantime"})
+		return
+	}
+	preconditionFailed(c, cnt)
+}
+
+func preconditionFailed(c *gin.Context, cnt content.Content) {
+	c.Header("ETag", cnt.ETag())
+	c.JSON(http.StatusPreconditionFailed, gin.H{
+		"error":   "Content has been modified in the meantime",
+		"version": cnt.Version,
+		"etag":    cnt.ETag(),
+	})
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"example.com/portfolio/content"
+	"example.com/portfolio/reactions"
+	"example.com/portfolio/validation"
+	"github.com/gin-gonic/gin"
+)
+
+type reactionInput struct {
+	Reaction string `json:"reaction"`
+}
+
+// react godoc
+// @Summary      React to content
+// @Description  Adds an anonymous reaction (like, love, clap or fire). Each visitor can leave each reaction once per item.
+// @Tags         reactions
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int            true  "Blog ID"
+// @Param        reaction  body      reactionInput  true  "Reaction"
+// @Success      200       {object}  map[string]interface{}  "Already reacted"
+// @Success      201       {object}  map[string]interface{}  "Reaction added"
+// @Failure      400       {object}  map[string]string       "Invalid input"
+// @Failure      404       {object}  map[string]string       "Blog not found"
+// @Failure      422       {object}  map[string]interface{}  "Validation failed; lists each failing field"
+// @Failure      500       {object}  map[string]string       "Failed to save reaction"
+// @Router       /blog/{id}/reactions [post]
+func react(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blog ID"})
+		return
+	}
+
+	var in reactionInput
+	if !bindJSON(c, &in) {
+		return
+	}
+	var errs validation.Errors
+	if errs.Required("reaction", in.Reaction) {
+		errs.OneOf("reaction", in.Reaction, reactions.Kinds)
+	}
+	if len(errs) > 0 {
+		invalidInput(c, errs)
+		return
+	}
+
+	// Only published items can be reacted to; drafts are not public.
+	if rev, err := content.Revision(id); err != nil || rev.Status != content.StatusPublished {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+
+	fp := reactions.Fingerprint(c.ClientIP(), c.Request.UserAgent())
+	added, err := reactions.Add(id, in.Reaction, fp)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save reaction"})
+		return
+	}
+
+	counts, err := reactions.Counts([]int64{id})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count reactions"})
+		return
+	}
+
+	if !added {
+		c.JSON(http.StatusOK, gin.H{"message": "You have already reacted", "reactions": counts[id]})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Reaction added", "reactions": counts[id]})
+}
+
+// unreact godoc
+// @Summary      Remove a reaction
+// @Description  Removes the visitor's own reaction from a content item
+// @Tags         reactions
+// @Produce      json
+// @Param        id        path      int     true  "Blog ID"
+// @Param        reaction  path      string  true  "Reaction"  Enums(like, love, clap, fire)
+// @Success      200       {object}  map[string]interface{}  "Reaction removed"
+// @Failure      400       {object}  map[string]string       "Invalid input"
+// @Failure      500       {object}  map[string]string       "Failed to remove reaction"
+// @Router       /blog/{id}/reactions/{reaction} [delete]
+func unreact(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blog ID"})
+		return
+	}
+
+	reaction := c.Param("reaction")
+	if !reactions.Valid(reaction) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Reaction must be one of like, love, clap, fire"})
+		return
+	}
+
+	fp := reactions.Fingerprint(c.ClientIP(), c.Request.UserAgent())
+	if err := reactions.Remove(id, reaction, fp); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove reaction"})
+		return
+	}
+
+	counts, err := reactions.Counts([]int64{id})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count reactions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reaction removed", "reactions": counts[id]})
+}
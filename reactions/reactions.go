@@ -0,0 +1,142 @@
+package reactions
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"example.com/portfolio/db"
+)
+
+// Kinds is the fixed set of reactions visitors can leave.
+var Kinds = []string{"like", "love", "clap", "fire"}
+
+func Valid(reaction string) bool {
+	for _, k := range Kinds {
+		if k == reaction {
+			return true
+		}
+	}
+	return false
+}
+
+// Fingerprint identifies an anonymous visitor by hashing their IP and user
+// agent with a server-side salt, so the raw values are never stored.
+func Fingerprint(ip, userAgent string) string {
+	salt := os.Getenv("REACTION_SALT")
+	if salt == "" {
+		salt = os.Getenv("JWT_SECRET")
+	}
+	sum := sha256.Sum256([]byte(salt + "|" + ip + "|" + userAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+// Add records a reaction. It returns false if the visitor has already left
+// the same reaction on this content.
+func Add(contentID int64, reaction, fingerprint string) (bool, error) {
+	res, err := db.DB.ExecContext(context.Background(), `
+		INSERT OR IGNORE INTO reactions (content_id, reaction, fingerprint)
+		VALUES (?, ?, ?)
+	`, contentID, reaction, fingerprint)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert reaction: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func Remove(contentID int64, reaction, fingerprint string) error {
+	_, err := db.DB.ExecContext(context.Background(), `
+		DELETE FROM reactions
+		WHERE content_id = ? AND reaction = ? AND fingerprint = ?
+	`, contentID, reaction, fingerprint)
+	if err != nil {
+		return fmt.Errorf("failed to delete reaction: %w", err)
+	}
+	return nil
+}
+
+// Changes returns how many times the reactions to a content item have
+// changed and when they last did, or 0 and "" if they never have. It
+// serves as a validator of the reaction counts.
+func Changes(contentID int64) (int64, string, error) {
+	var (
+		n       int64
+		changed string
+	)
+	err := db.DB.QueryRowContext(context.Background(),
+		"SELECT changes, COALESCE(changed_at, '') FROM reaction_changes WHERE content_id = ?", contentID).
+		Scan(&n, &changed)
+	if err == sql.ErrNoRows {
+		return 0, "", nil
+	}
+	return n, changed, err
+}
+
+// AllChanges is Changes over all content.
+func AllChanges() (int64, string, error) {
+	var (
+		n       int64
+		changed string
+	)
+	err := db.DB.QueryRowContext(context.Background(),
+		"SELECT COALESCE(SUM(changes), 0), COALESCE(MAX(changed_at), '') FROM reaction_changes").
+		Scan(&n, &changed)
+	return n, changed, err
+}
+
+// Counts returns the number of each reaction per content ID. Content
+// without reactions is absent from the result.
+func Counts(ids []int64) (map[int64]map[string]int, error) {
+	counts := map[int64]map[string]int{}
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT content_id, reaction, COUNT(*)
+		FROM reactions
+		WHERE content_id IN (`+placeholders+`)
+		GROUP BY content_id, reaction
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id       int64
+			reaction string
+			n        int
+		)
+		if err := rows.Scan(&id, &reaction, &n); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if counts[id] == nil {
+			counts[id] = map[string]int{}
+		}
+		counts[id][reaction] = n
+	}
+	return counts, rows.Err()
+}
+
+// DeleteForContentTx removes all reactions to a content item within tx, in
+// which the caller deletes the content item itself.
+func DeleteForContentTx(ctx context.Context, tx *sql.Tx, contentID int64) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM reactions WHERE content_id = ?", contentID)
+	return err
+}
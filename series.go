@@ -0,0 +1,311 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"example.com/portfolio/content"
+	"example.com/portfolio/validation"
+	"example.com/portfolio/webhooks"
+	"github.com/gin-gonic/gin"
+)
+
+// listSeries godoc
+// @Summary      List series
+// @Description  Returns all series, newest first, without their items
+// @Tags         series
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "Series"
+// @Failure      500  {object}  map[string]string       "Failed to fetch series"
+// @Router       /series [get]
+func listSeries(c *gin.Context) {
+	list, err := content.ListSeries()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"series": list})
+}
+
+// getSeries godoc
+// @Summary      Get a series
+// @Description  Returns a series with its parts in reading order
+// @Tags         series
+// @Produce      json
+// @Param        id   path      int  true  "Series ID"
+// @Success      200  {object}  content.Series
+// @Failure      400  {object}  map[string]string  "Invalid series ID"
+// @Failure      404  {object}  map[string]string  "Series not found"
+// @Failure      500  {object}  map[string]string  "Failed to fetch series"
+// @Router       /series/{id} [get]
+func getSeries(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	s, err := content.GetSeries(id)
+	if err != nil {
+		if errors.Is(err, content.ErrSeriesNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, s)
+}
+
+// createSeries godoc
+// @Summary      Create a series
+// @Description  Creates an empty series. Parts are added with PUT /series/{id}/items.
+// @Security     TokenAuth
+// @Tags         series
+// @Accept       json
+// @Produce      json
+// @Param        series  body      content.Series  true  "Title and description"
+// @Success      201     {object}  content.Series
+// @Failure      400     {object}  map[string]string  "Invalid input"
+// @Failure      422     {object}  map[string]interface{}  "Validation failed; lists each failing field"
+// @Failure      500     {object}  map[string]string  "Failed to create series"
+// @Router       /series [post]
+func createSeries(c *gin.Context) {
+	var s content.Series
+	if !bindJSON(c, &s) {
+		return
+	}
+
+	if err := s.Add(); err != nil {
+		if errors.Is(err, content.ErrInvalid) {
+			invalidInput(c, err)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create series"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, s)
+}
+
+// editSeries godoc
+// @Summary      Update a series
+// @Description  Updates the title and description of a series
+// @Security     TokenAuth
+// @Tags         series
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int             true  "Series ID"
+// @Param        series  body      content.Series  true  "Title and description"
+// @Success      200     {object}  map[string]string  "Series updated"
+// @Failure      400     {object}  map[string]string  "Invalid input"
+// @Failure      404     {object}  map[string]string  "Series not found"
+// @Failure      422     {object}  map[string]interface{}  "Validation failed; lists each failing field"
+// @Failure      500     {object}  map[string]string  "Failed to update series"
+// @Router       /series/{id} [put]
+func editSeries(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	var s content.Series
+	if !bindJSON(c, &s) {
+		return
+	}
+	s.ID = id
+
+	if err := s.Update(); err != nil {
+		switch {
+		case errors.Is(err, content.ErrSeriesNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+		case errors.Is(err, content.ErrInvalid):
+			invalidInput(c, err)
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update series"})
+		}
+		return
+	}
+
+	emitChanged(webhooks.EventContentUpdated, content.SeriesMembers(id))
+	c.JSON(http.StatusOK, gin.H{"message": "Series updated successfully"})
+}
+
+// deleteSeries godoc
+// @Summary      Delete a series
+// @Description  Deletes a series. Its parts are kept as standalone content.
+// @Security     TokenAuth
+// @Tags         series
+// @Produce      json
+// @Param        id   path      int  true  "Series ID"
+// @Success      200  {object}  map[string]string  "Series deleted"
+// @Failure      400  {object}  map[string]string  "Invalid series ID"
+// @Failure      404  {object}  map[string]string  "Series not found"
+// @Failure      500  {object}  map[string]string  "Failed to delete series"
+// @Router       /series/{id} [delete]
+func deleteSeries(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	members := content.SeriesMembers(id)
+	if err := content.DeleteSeries(id); err != nil {
+		if errors.Is(err, content.ErrSeriesNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete series"})
+		return
+	}
+
+	emitChanged(webhooks.EventContentUpdated, members)
+	c.JSON(http.StatusOK, gin.H{"message": "Series deleted successfully"})
+}
+
+type seriesItems struct {
+	ContentIDs []int64 `json:"content_ids"`
+}
+
+type seriesItem struct {
+	ContentID int64 `json:"content_id"`
+}
+
+// setSeriesItems godoc
+// @Summary      Set the parts of a series
+// @Description  Replaces the parts of a series with the given content IDs, in reading order. A content item can belong to only one series.
+// @Security     TokenAuth
+// @Tags         series
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int          true  "Series ID"
+// @Param        items  body      seriesItems  true  "Ordered content IDs"
+// @Success      200    {object}  content.Series
+// @Failure      400    {object}  map[string]string  "Invalid input"
+// @Failure      404    {object}  map[string]string  "Series not found"
+// @Failure      422    {object}  map[string]interface{}  "Validation failed; lists each failing field"
+// @Failure      500    {object}  map[string]string  "Failed to update series"
+// @Router       /series/{id}/items [put]
+func setSeriesItems(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	var in seriesItems
+	if !bindJSON(c, &in) {
+		return
+	}
+	if in.ContentIDs == nil {
+		invalidInput(c, validation.Errors{{Field: "content_ids", Reason: "is required"}})
+		return
+	}
+
+	before := content.SeriesMembers(id)
+	respondSeriesItems(c, id, before, content.SetSeriesItems(id, in.ContentIDs))
+}
+
+// addSeriesItem godoc
+// @Summary      Append a part to a series
+// @Description  Adds a content item as the last part of a series
+// @Security     TokenAuth
+// @Tags         series
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int         true  "Series ID"
+// @Param        item  body      seriesItem  true  "Content ID"
+// @Success      200   {object}  content.Series
+// @Failure      400   {object}  map[string]string  "Invalid input"
+// @Failure      404   {object}  map[string]string  "Series not found"
+// @Failure      422   {object}  map[string]interface{}  "Validation failed; lists each failing field"
+// @Failure      500   {object}  map[string]string  "Failed to update series"
+// @Router       /series/{id}/items [post]
+func addSeriesItem(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	var in seriesItem
+	if !bindJSON(c, &in) {
+		return
+	}
+	if in.ContentID == 0 {
+		invalidInput(c, validation.Errors{{Field: "content_id", Reason: "is required"}})
+		return
+	}
+
+	before := content.SeriesMembers(id)
+	respondSeriesItems(c, id, before, content.AddSeriesItem(id, in.ContentID))
+}
+
+// removeSeriesItem godoc
+// @Summary      Remove a part from a series
+// @Description  Removes a content item from a series. The remaining parts keep their order.
+// @Security     TokenAuth
+// @Tags         series
+// @Produce      json
+// @Param        id       path      int  true  "Series ID"
+// @Param        content  path      int  true  "Content ID"
+// @Success      200      {object}  content.Series
+// @Failure      400      {object}  map[string]string  "Invalid input"
+// @Failure      404      {object}  map[string]string  "Series not found"
+// @Failure      500      {object}  map[string]string  "Failed to update series"
+// @Router       /series/{id}/items/{content} [delete]
+func removeSeriesItem(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	contentID, err := strconv.ParseInt(c.Param("content"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	before := content.SeriesMembers(id)
+	respondSeriesItems(c, id, before, content.RemoveSeriesItem(id, contentID))
+}
+
+// respondSeriesItems reports the outcome of a membership change, returning
+// the updated series on success. The items that were in the series before
+// and those in it now are announced as updated.
+func respondSeriesItems(c *gin.Context, id int64, before []int64, err error) {
+	switch {
+	case errors.Is(err, content.ErrSeriesNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+		return
+	case errors.Is(err, content.ErrInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update series"})
+		return
+	}
+
+	changed := before
+	for _, m := range content.SeriesMembers(id) {
+		if !slices.Contains(before, m) {
+			changed = append(changed, m)
+		}
+	}
+	emitChanged(webhooks.EventContentUpdated, changed)
+
+	s, err := content.GetSeries(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, s)
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"errors"
 	"os"
 	"time"
@@ -42,3 +44,56 @@ func Check(token string) error {
 	}
 	return nil
 }
+
+// GenerateActionToken signs a link token that allows one action, such as
+// confirming a newsletter subscription, for subject. It is signed with a
+// key derived from JWT_SECRET and purpose, so Check never accepts it as a
+// login and it cannot be used for another purpose. A zero ttl never
+// expires.
+func GenerateActionToken(purpose, subject string, ttl time.Duration) (string, error) {
+	key, err := actionKey(purpose)
+	if err != nil {
+		return "", err
+	}
+
+	claims := jwt.MapClaims{"sub": subject, "purpose": purpose}
+	if ttl > 0 {
+		claims["exp"] = time.Now().Add(ttl).Unix()
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+}
+
+// CheckActionToken returns the subject of a token made by
+// GenerateActionToken for purpose.
+func CheckActionToken(purpose, token string) (string, error) {
+	key, err := actionKey(purpose)
+	if err != nil {
+		return "", err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return "", err
+	}
+	if claims["purpose"] != purpose {
+		return "", errors.New("Invalid token")
+	}
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return "", errors.New("Invalid token")
+	}
+	return subject, nil
+}
+
+func actionKey(purpose string) ([]byte, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return nil, errors.New("JWT_SECRET not set in environment")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("action:" + purpose))
+	return mac.Sum(nil), nil
+}
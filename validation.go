@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"example.com/portfolio/validation"
+	"github.com/gin-gonic/gin"
+)
+
+// invalidInput responds to input the models rejected: field errors with
+// 422 and the list of failing fields, anything else with 400.
+func invalidInput(c *gin.Context, err error) {
+	var errs validation.Errors
+	if errors.As(err, &errs) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Validation failed", "fields": errs})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// bindJSON decodes the request body into v and reports whether it
+// succeeded. Malformed JSON is answered with 400 and a value of the wrong
+// type with 422. Unlike ShouldBindJSON it ignores binding tags: required
+// fields are checked by the model together with its other rules, so that
+// every failing field is reported at once.
+func bindJSON(c *gin.Context, v interface{}) bool {
+	err := json.NewDecoder(c.Request.Body).Decode(v)
+	if err == nil {
+		return true
+	}
+	if errs, ok := validation.TypeError(err); ok {
+		invalidInput(c, errs)
+		return false
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	return false
+}
@@ -0,0 +1,158 @@
+// Package validation collects field-level input errors, so that a request
+// is answered with every problem at once rather than the first one found.
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// FieldError is one failed rule. Field is the JSON name of the input,
+// with nested objects separated by dots, e.g. project.repo_url.
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// Errors is a list of failed fields. Handlers answer it with 422 and the
+// list; wrapped in a package's sentinel error it still matches errors.As.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		parts[i] = fe.Field + " " + fe.Reason
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Err returns e as an error, or nil if no rule failed.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *Errors) Add(field, reason string) {
+	*e = append(*e, FieldError{Field: field, Reason: reason})
+}
+
+func (e *Errors) Addf(field, format string, args ...interface{}) {
+	e.Add(field, fmt.Sprintf(format, args...))
+}
+
+// Nest adds the errors of a nested object under prefix.
+func (e *Errors) Nest(prefix string, nested Errors) {
+	for _, fe := range nested {
+		e.Add(prefix+"."+fe.Field, fe.Reason)
+	}
+}
+
+// The rules below record an error and return false when value fails.
+// Apart from Required, they accept an empty value.
+
+func (e *Errors) Required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		e.Add(field, "is required")
+		return false
+	}
+	return true
+}
+
+func (e *Errors) MaxLength(field, value string, max int) bool {
+	if len([]rune(value)) > max {
+		e.Addf(field, "must be at most %d characters", max)
+		return false
+	}
+	return true
+}
+
+func (e *Errors) MinLength(field, value string, min int) bool {
+	if value != "" && len([]rune(value)) < min {
+		e.Addf(field, "must be at least %d characters", min)
+		return false
+	}
+	return true
+}
+
+func (e *Errors) OneOf(field, value string, allowed []string) bool {
+	if value == "" {
+		return true
+	}
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	e.Addf(field, "must be one of %s", strings.Join(allowed, ", "))
+	return false
+}
+
+// URL accepts absolute http and https URLs.
+func (e *Errors) URL(field, value string) bool {
+	if value == "" {
+		return true
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		e.Add(field, "must be an http(s) URL")
+		return false
+	}
+	return true
+}
+
+// Email accepts a bare address such as name@example.com.
+func (e *Errors) Email(field, value string) bool {
+	if value == "" {
+		return true
+	}
+	a, err := mail.ParseAddress(value)
+	if err != nil || a.Address != value || !strings.Contains(a.Address[strings.LastIndex(a.Address, "@"):], ".") {
+		e.Add(field, "must be a valid email address")
+		return false
+	}
+	return true
+}
+
+func (e *Errors) Match(field, value string, re *regexp.Regexp, reason string) bool {
+	if value != "" && !re.MatchString(value) {
+		e.Add(field, reason)
+		return false
+	}
+	return true
+}
+
+// TypeError converts a JSON value of the wrong type, as reported by
+// encoding/json, into a field error.
+func TypeError(err error) (Errors, bool) {
+	var te *json.UnmarshalTypeError
+	if !errors.As(err, &te) || te.Field == "" {
+		return nil, false
+	}
+	return Errors{{Field: te.Field, Reason: "must be " + kind(te.Type)}}, true
+}
+
+func kind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	case reflect.Struct, reflect.Ptr, reflect.Map:
+		return "an object"
+	}
+	return "a " + t.String()
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"example.com/portfolio/content"
+	"example.com/portfolio/webhooks"
+	"github.com/gin-gonic/gin"
+)
+
+// createWebhook godoc
+// @Summary      Register a webhook
+// @Description  Registers an endpoint for events (content.created, content.updated, content.deleted, request.created). content.updated is also sent when the gallery, series or comments of an item change; request.created carries only the request's ID, name and time. Each event is POSTed as JSON with an X-Webhook-Signature header: t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<body>">, keyed with the secret returned here, which is not shown again.
+// @Security     TokenAuth
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        webhook  body      webhooks.Webhook  true  "URL, events and active flag (default true)"
+// @Success      201      {object}  webhooks.Webhook
+// @Failure      400      {object}  map[string]string       "Invalid request body"
+// @Failure      422      {object}  map[string]interface{}  "Validation failed; lists each failing field"
+// @Failure      500      {object}  map[string]string       "Failed to create webhook"
+// @Router       /webhooks [post]
+func createWebhook(c *gin.Context) {
+	var w webhooks.Webhook
+	if !bindJSON(c, &w) {
+		return
+	}
+
+	if err := w.Add(); err != nil {
+		if errors.Is(err, webhooks.ErrInvalid) {
+			invalidInput(c, err)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create webhook"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, w)
+}
+
+// listWebhooks godoc
+// @Summary      List webhooks
+// @Security     TokenAuth
+// @Tags         webhooks
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "Webhooks, without their secrets"
+// @Failure      500  {object}  map[string]string       "Failed to fetch webhooks"
+// @Router       /webhooks [get]
+func listWebhooks(c *gin.Context) {
+	list, err := webhooks.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhooks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhooks": list, "events": webhooks.Events})
+}
+
+// editWebhook godoc
+// @Summary      Update a webhook
+// @Description  Changes the URL and events of a webhook and, if given, switches it on or off. Deliveries queued while it is off are sent once it is on again. The secret is kept.
+// @Security     TokenAuth
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int               true  "Webhook ID"
+// @Param        webhook  body      webhooks.Webhook  true  "URL, events and active flag"
+// @Success      200      {object}  webhooks.Webhook
+// @Failure      400      {object}  map[string]string       "Invalid request body"
+// @Failure      404      {object}  map[string]string       "Webhook not found"
+// @Failure      422      {object}  map[string]interface{}  "Validation failed; lists each failing field"
+// @Failure      500      {object}  map[string]string       "Failed to update webhook"
+// @Router       /webhooks/{id} [put]
+func editWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	var w webhooks.Webhook
+	if !bindJSON(c, &w) {
+		return
+	}
+	w.ID = id
+
+	if err := w.Update(); err != nil {
+		switch {
+		case errors.Is(err, webhooks.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		case errors.Is(err, webhooks.ErrInvalid):
+			invalidInput(c, err)
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update webhook"})
+		}
+		return
+	}
+
+	updated, err := webhooks.Get(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhook"})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// deleteWebhook godoc
+// @Summary      Delete a webhook
+// @Description  Deletes a webhook and its delivery log
+// @Security     TokenAuth
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      int  true  "Webhook ID"
+// @Success      200  {object}  map[string]string  "Webhook deleted"
+// @Failure      400  {object}  map[string]string  "Invalid webhook ID"
+// @Failure      404  {object}  map[string]string  "Webhook not found"
+// @Failure      500  {object}  map[string]string  "Failed to delete webhook"
+// @Router       /webhooks/{id} [delete]
+func deleteWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	if err := webhooks.Delete(id); err != nil {
+		if errors.Is(err, webhooks.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete webhook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// pingWebhook godoc
+// @Summary      Test a webhook
+// @Description  Queues a ping event for the webhook, even if it is switched off. The outcome appears in its delivery log.
+// @Security     TokenAuth
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      int  true  "Webhook ID"
+// @Success      202  {object}  webhooks.Delivery
+// @Failure      400  {object}  map[string]string  "Invalid webhook ID"
+// @Failure      404  {object}  map[string]string  "Webhook not found"
+// @Failure      500  {object}  map[string]string  "Failed to queue ping"
+// @Router       /webhooks/{id}/ping [post]
+func pingWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	d, err := webhooks.Ping(id)
+	if err != nil {
+		if errors.Is(err, webhooks.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to queue ping"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, d)
+}
+
+// listDeliveries godoc
+// @Summary      Webhook delivery log
+// @Description  Returns the deliveries of a webhook, newest first, with the number of attempts, the last response or error and, for pending ones, the time of the next attempt.
+// @Security     TokenAuth
+// @Tags         webhooks
+// @Produce      json
+// @Param        id      path      int     true   "Webhook ID"
+// @Param        status  query     string  false  "Delivery status"  Enums(pending, delivered, failed)
+// @Param        page    query     int     false  "Page number (default 1)"
+// @Success      200     {object}  map[string]interface{}  "Deliveries"
+// @Failure      400     {object}  map[string]string       "Invalid parameters"
+// @Failure      404     {object}  map[string]string       "Webhook not found"
+// @Failure      500     {object}  map[string]string       "Failed to fetch deliveries"
+// @Router       /webhooks/{id}/deliveries [get]
+func listDeliveries(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	status := c.Query("status")
+	if status != "" && !webhooks.ValidStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page number"})
+		return
+	}
+
+	if _, err := webhooks.Get(id); err != nil {
+		if errors.Is(err, webhooks.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deliveries"})
+		return
+	}
+
+	list, err := webhooks.Deliveries(id, status, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deliveries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": list})
+}
+
+// redeliver godoc
+// @Summary      Redeliver a webhook event
+// @Description  Queues the payload of a delivery again as a new delivery, e.g. after fixing the endpoint. The payload keeps its event id, so receivers can recognize the duplicate.
+// @Security     TokenAuth
+// @Tags         webhooks
+// @Produce      json
+// @Param        id        path      int  true  "Webhook ID"
+// @Param        delivery  path      int  true  "Delivery ID"
+// @Success      202       {object}  webhooks.Delivery
+// @Failure      400       {object}  map[string]string  "Invalid ID"
+// @Failure      404       {object}  map[string]string  "Delivery not found"
+// @Failure      500       {object}  map[string]string  "Failed to queue delivery"
+// @Router       /webhooks/{id}/deliveries/{delivery}/redeliver [post]
+func redeliver(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := strconv.ParseInt(c.Param("delivery"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return
+	}
+
+	d, err := webhooks.Redeliver(id, deliveryID)
+	if err != nil {
+		if errors.Is(err, webhooks.ErrDeliveryNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to queue delivery"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, d)
+}
+
+func webhookID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+// deletedContent is the data of a content.deleted event.
+type deletedContent struct {
+	ID int64 `json:"id"`
+}
+
+// createdRequest is the data of a request.created event. The contact
+// details and IP of the requester are left out; they are only sent to
+// the admin's Telegram.
+type createdRequest struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// emitChanged sends content.created or content.updated for items changed
+// in bulk, such as by an import, with the current version of each item.
+func emitChanged(event string, ids []int64) {
+	if len(ids) == 0 {
+		return
+	}
+	go func() {
+		for _, id := range ids {
+			cnt, err := content.GetById(id)
+			if err != nil {
+				log.Printf("⚠️ Could not load content %d for webhooks: %v", id, err)
+				continue
+			}
+			webhooks.Emit(event, cnt)
+		}
+	}()
+}
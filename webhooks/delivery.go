@@ -0,0 +1,271 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/portfolio/db"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+
+	pageSize = 20
+)
+
+var ErrDeliveryNotFound = errors.New("delivery not found")
+
+// Delivery is one event sent, or to be sent, to one webhook. A redelivery
+// is a new delivery of the same payload.
+type Delivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int64           `json:"webhook_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *string         `json:"next_attempt_at,omitempty"`
+	ResponseStatus *int            `json:"response_status,omitempty"`
+	ResponseBody   *string         `json:"response_body,omitempty"`
+	Error          *string         `json:"error,omitempty"`
+	RedeliveryOf   *int64          `json:"redelivery_of,omitempty"`
+	CreatedAt      string          `json:"created_at"`
+	DeliveredAt    *string         `json:"delivered_at,omitempty"`
+}
+
+// Payload is the JSON body of every delivery. ID identifies the event and
+// is the same for all its deliveries, so receivers can drop duplicates.
+type Payload struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// Emit queues event for every active webhook subscribed to it, in the
+// background, and wakes the worker.
+func Emit(event string, data interface{}) {
+	go func() {
+		if _, err := enqueue(event, data, 0); err != nil {
+			log.Printf("⚠️ Could not queue %s webhooks: %v", event, err)
+		}
+	}()
+}
+
+// Ping queues a ping event for webhook id, active or not, so that an
+// endpoint can be tested.
+func Ping(id int64) (Delivery, error) {
+	ids, err := enqueue(EventPing, map[string]interface{}{"webhook_id": id}, id)
+	if err != nil {
+		return Delivery{}, err
+	}
+	if len(ids) == 0 {
+		return Delivery{}, ErrNotFound
+	}
+	return GetDelivery(id, ids[0])
+}
+
+// enqueue stores a delivery of event for each subscribed webhook, or only
+// for webhook only if it is set, and returns the delivery IDs.
+func enqueue(event string, data interface{}, only int64) ([]int64, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate event ID: %w", err)
+	}
+	body, err := json.Marshal(Payload{
+		ID:        hex.EncodeToString(id),
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	var rows *sql.Rows
+	if only != 0 {
+		rows, err = db.DB.QueryContext(context.Background(), "SELECT id FROM webhooks WHERE id = ?", only)
+	} else {
+		rows, err = db.DB.QueryContext(context.Background(), `
+			SELECT id FROM webhooks
+			WHERE active = 1 AND instr(',' || events || ',', ',' || ? || ',') > 0
+		`, event)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhooks: %w", err)
+	}
+	var hooks []int64
+	for rows.Next() {
+		var h int64
+		if err := rows.Scan(&h); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		hooks = append(hooks, h)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, h := range hooks {
+		id, err := insertDelivery(h, event, string(body), nil)
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) > 0 {
+		notify()
+	}
+	return ids, nil
+}
+
+func insertDelivery(webhookID int64, event, payload string, redeliveryOf *int64) (int64, error) {
+	res, err := db.DB.ExecContext(context.Background(), `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload, redelivery_of, next_attempt_at)
+		VALUES (?, ?, ?, ?, datetime('now'))
+	`, webhookID, event, payload, redeliveryOf)
+	if err != nil {
+		return 0, fmt.Errorf("failed to queue delivery: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// Redeliver queues the payload of delivery id of webhook webhookID again,
+// whatever became of it, and returns the new delivery.
+func Redeliver(webhookID, id int64) (Delivery, error) {
+	d, err := GetDelivery(webhookID, id)
+	if err != nil {
+		return d, err
+	}
+	newID, err := insertDelivery(webhookID, d.Event, string(d.Payload), &d.ID)
+	if err != nil {
+		return d, err
+	}
+	notify()
+	return GetDelivery(webhookID, newID)
+}
+
+const deliveryColumns = `
+	id, webhook_id, event, payload, status, attempts, next_attempt_at,
+	response_status, response_body, error, redelivery_of, created_at, delivered_at
+`
+
+// Deliveries returns a page of the delivery log of webhook webhookID,
+// newest first.
+func Deliveries(webhookID int64, status string, page int) ([]Delivery, error) {
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries
+		WHERE webhook_id = ? AND (? = '' OR status = ?)
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?
+	`, webhookID, status, status, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []Delivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func GetDelivery(webhookID, id int64) (Delivery, error) {
+	d, err := scanDelivery(db.DB.QueryRowContext(context.Background(),
+		"SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE id = ? AND webhook_id = ?", id, webhookID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return d, ErrDeliveryNotFound
+	}
+	return d, err
+}
+
+func ValidStatus(status string) bool {
+	return status == StatusPending || status == StatusDelivered || status == StatusFailed
+}
+
+func scanDelivery(s scanner) (Delivery, error) {
+	var (
+		d       Delivery
+		payload string
+	)
+	err := s.Scan(&d.ID, &d.WebhookID, &d.Event, &payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&d.ResponseStatus, &d.ResponseBody, &d.Error, &d.RedeliveryOf, &d.CreatedAt, &d.DeliveredAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return d, err
+		}
+		return d, fmt.Errorf("failed to scan delivery: %w", err)
+	}
+	d.Payload = json.RawMessage(payload)
+	return d, nil
+}
+
+// SignatureHeader carries the signature of a delivery, in the form
+// t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<body>">, keyed with
+// the webhook's secret. Signing the time lets receivers reject replays.
+const SignatureHeader = "X-Webhook-Signature"
+
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + signature(secret, ts, body)
+}
+
+func signature(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature header of a delivery received with body. It
+// fails if the signature does not match or is older than tolerance.
+func Verify(secret, header string, body []byte, tolerance time.Duration) error {
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return errors.New("malformed signature header")
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return errors.New("signature timestamp is outside the tolerance")
+	}
+
+	want := signature(secret, ts, body)
+	for _, sig := range sigs {
+		if hmac.Equal([]byte(sig), []byte(want)) {
+			return nil
+		}
+	}
+	return errors.New("signature does not match")
+}
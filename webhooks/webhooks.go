@@ -0,0 +1,189 @@
+// Package webhooks notifies external endpoints, such as a frontend build
+// hook, of changes to content and of new portfolio requests. Every event
+// is stored as a delivery per subscribed endpoint and sent by a background
+// worker, which retries failed deliveries with exponential backoff.
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"example.com/portfolio/db"
+	"example.com/portfolio/validation"
+)
+
+const (
+	EventContentCreated = "content.created"
+	EventContentUpdated = "content.updated"
+	EventContentDeleted = "content.deleted"
+	EventRequestCreated = "request.created"
+	// EventPing is only sent on request, to test an endpoint.
+	EventPing = "ping"
+)
+
+// Events are the events an endpoint can subscribe to.
+var Events = []string{EventContentCreated, EventContentUpdated, EventContentDeleted, EventRequestCreated}
+
+var (
+	ErrNotFound = errors.New("webhook not found")
+	ErrInvalid  = errors.New("invalid webhook")
+)
+
+// Webhook is an endpoint subscribed to events. Its secret signs the
+// payloads; it is only returned when the webhook is created.
+type Webhook struct {
+	ID        int64    `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Active    *bool    `json:"active,omitempty"`
+	Secret    string   `json:"secret,omitempty"`
+	CreatedAt string   `json:"created_at"`
+}
+
+func (w *Webhook) Validate() error {
+	w.URL = strings.TrimSpace(w.URL)
+	var errs validation.Errors
+	if errs.Required("url", w.URL) {
+		errs.URL("url", w.URL)
+	}
+	if len(w.Events) == 0 {
+		errs.Add("events", "must list at least one event")
+	}
+	for _, e := range w.Events {
+		if !errs.OneOf("events", e, Events) {
+			break
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalid, errs)
+	}
+	return nil
+}
+
+func (w *Webhook) isActive() bool {
+	return w.Active == nil || *w.Active
+}
+
+// Add registers w with a new random secret.
+func (w *Webhook) Add() error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return fmt.Errorf("failed to generate secret: %w", err)
+	}
+	w.Secret = "whsec_" + hex.EncodeToString(secret)
+	active := w.isActive()
+	w.Active = &active
+
+	res, err := db.DB.ExecContext(context.Background(),
+		"INSERT INTO webhooks (url, secret, events, active) VALUES (?, ?, ?, ?)",
+		w.URL, w.Secret, strings.Join(w.Events, ","), active)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	w.ID, _ = res.LastInsertId()
+
+	return db.DB.QueryRowContext(context.Background(),
+		"SELECT created_at FROM webhooks WHERE id = ?", w.ID).Scan(&w.CreatedAt)
+}
+
+// Update changes the URL, events and, if set, the active flag of webhook
+// w.ID. The secret is kept.
+func (w *Webhook) Update() error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+
+	res, err := db.DB.ExecContext(context.Background(), `
+		UPDATE webhooks SET url = ?, events = ?, active = COALESCE(?, active)
+		WHERE id = ?
+	`, w.URL, strings.Join(w.Events, ","), w.Active, w.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func List() ([]Webhook, error) {
+	rows, err := db.DB.QueryContext(context.Background(),
+		"SELECT id, url, events, active, created_at FROM webhooks ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	hooks := []Webhook{}
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, w)
+	}
+	return hooks, rows.Err()
+}
+
+func Get(id int64) (Webhook, error) {
+	w, err := scanWebhook(db.DB.QueryRowContext(context.Background(),
+		"SELECT id, url, events, active, created_at FROM webhooks WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return w, ErrNotFound
+	}
+	return w, err
+}
+
+// Delete removes webhook id and its delivery log.
+func Delete(id int64) error {
+	ctx := context.Background()
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM webhooks WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM webhook_deliveries WHERE webhook_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete deliveries: %w", err)
+	}
+	return tx.Commit()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWebhook(s scanner) (Webhook, error) {
+	var (
+		w      Webhook
+		events string
+		active bool
+	)
+	if err := s.Scan(&w.ID, &w.URL, &events, &active, &w.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return w, err
+		}
+		return w, fmt.Errorf("failed to scan webhook: %w", err)
+	}
+	w.Events = strings.Split(events, ",")
+	w.Active = &active
+	return w, nil
+}
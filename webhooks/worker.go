@@ -0,0 +1,220 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"example.com/portfolio/db"
+)
+
+const (
+	// A failed delivery is retried after 30s, 1m, 2m, ... and marked as
+	// failed after maxAttempts attempts, about an hour after the event.
+	maxAttempts = 8
+	baseDelay   = 30 * time.Second
+
+	pollInterval    = 15 * time.Second
+	requestTimeout  = 10 * time.Second
+	batchSize       = 20
+	maxResponseBody = 2048
+)
+
+var (
+	wake = make(chan struct{}, 1)
+
+	client = &http.Client{
+		Timeout: requestTimeout,
+		// A redirect is reported as a failure rather than followed, since
+		// it would turn the POST into a GET.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+)
+
+// notify wakes the worker without waiting for it.
+func notify() {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start runs the delivery worker in the background until ctx is done.
+// Deliveries left pending by an earlier run, or queued by another process,
+// are picked up as well.
+func Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			deliverDue(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-wake:
+			}
+		}
+	}()
+}
+
+type job struct {
+	id        int64
+	event     string
+	payload   []byte
+	attempts  int
+	scheduled string
+	url       string
+	secret    string
+}
+
+func deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		jobs, err := due()
+		if err != nil {
+			log.Printf("⚠️ Could not load webhook deliveries: %v", err)
+			return
+		}
+		for _, j := range jobs {
+			if claim(j) {
+				finish(j, send(ctx, j))
+			}
+		}
+		if len(jobs) < batchSize {
+			return
+		}
+	}
+}
+
+// due returns the pending deliveries whose next attempt is due. Webhooks
+// that are switched off keep their deliveries until they are switched on
+// again, except for pings.
+func due() ([]job, error) {
+	rows, err := db.DB.QueryContext(context.Background(), `
+		SELECT d.id, d.event, d.payload, d.attempts, d.next_attempt_at, w.url, w.secret
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = 'pending' AND d.next_attempt_at <= datetime('now')
+		  AND (w.active = 1 OR d.event = ?)
+		ORDER BY d.next_attempt_at, d.id
+		LIMIT ?
+	`, EventPing, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []job
+	for rows.Next() {
+		var (
+			j       job
+			payload string
+		)
+		if err := rows.Scan(&j.id, &j.event, &payload, &j.attempts, &j.scheduled, &j.url, &j.secret); err != nil {
+			return nil, err
+		}
+		j.payload = []byte(payload)
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+// claim moves the next attempt of j past the request timeout, so that a
+// second worker polling meanwhile does not send it too. It reports false
+// if another worker got there first.
+func claim(j job) bool {
+	res, err := db.DB.ExecContext(context.Background(), `
+		UPDATE webhook_deliveries SET next_attempt_at = datetime('now', ?)
+		WHERE id = ? AND status = 'pending' AND next_attempt_at = ?
+	`, seconds(2*requestTimeout), j.id, j.scheduled)
+	if err != nil {
+		log.Printf("⚠️ Could not claim webhook delivery %d: %v", j.id, err)
+		return false
+	}
+	n, _ := res.RowsAffected()
+	return n == 1
+}
+
+type result struct {
+	status int
+	body   string
+	err    error
+}
+
+func send(ctx context.Context, j job) result {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.url, bytes.NewReader(j.payload))
+	if err != nil {
+		return result{err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "portfolio-webhooks/1.0")
+	req.Header.Set("X-Webhook-Event", j.event)
+	req.Header.Set("X-Webhook-Delivery", fmt.Sprint(j.id))
+	req.Header.Set(SignatureHeader, Sign(j.secret, time.Now(), j.payload))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return result{err: err}
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	r := result{status: resp.StatusCode, body: string(body)}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		r.err = fmt.Errorf("endpoint responded with %s", resp.Status)
+	}
+	return r
+}
+
+// finish records the outcome of an attempt and schedules the next one.
+func finish(j job, r result) {
+	attempts := j.attempts + 1
+	var status *int
+	if r.status != 0 {
+		status = &r.status
+	}
+
+	var err error
+	switch {
+	case r.err == nil:
+		_, err = db.DB.ExecContext(context.Background(), `
+			UPDATE webhook_deliveries
+			SET status = 'delivered', attempts = ?, response_status = ?, response_body = ?,
+				error = NULL, next_attempt_at = NULL, delivered_at = datetime('now')
+			WHERE id = ?
+		`, attempts, status, r.body, j.id)
+	case attempts >= maxAttempts:
+		_, err = db.DB.ExecContext(context.Background(), `
+			UPDATE webhook_deliveries
+			SET status = 'failed', attempts = ?, response_status = ?, response_body = ?,
+				error = ?, next_attempt_at = NULL
+			WHERE id = ?
+		`, attempts, status, r.body, r.err.Error(), j.id)
+	default:
+		_, err = db.DB.ExecContext(context.Background(), `
+			UPDATE webhook_deliveries
+			SET attempts = ?, response_status = ?, response_body = ?, error = ?,
+				next_attempt_at = datetime('now', ?)
+			WHERE id = ?
+		`, attempts, status, r.body, r.err.Error(), seconds(backoff(attempts)), j.id)
+	}
+	if err != nil {
+		log.Printf("⚠️ Could not record webhook delivery %d: %v", j.id, err)
+	}
+}
+
+// backoff returns the delay before the retry that follows attempt n.
+func backoff(n int) time.Duration {
+	return baseDelay << (n - 1)
+}
+
+// seconds formats d as an SQLite datetime modifier.
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("+%d seconds", int(d.Seconds()))
+}